@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+func executeConfig() error {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help message")
+
+	fs.Usage = func() {
+		showConfigHelp()
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+
+	args := fs.Args()
+	if *help || len(args) == 0 {
+		showConfigHelp()
+		return nil
+	}
+
+	switch args[0] {
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: go-projo config get <key>")
+		}
+		return configGet(args[1])
+	case "set":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: go-projo config set <key> <value>")
+		}
+		return configSet(args[1], args[2])
+	case "list":
+		return configList()
+	case "path":
+		return configPath()
+	default:
+		return fmt.Errorf("unknown config command: %s\nRun 'go-projo config -help' for usage", args[0])
+	}
+}
+
+// effectiveDefaults merges the organization and user defaults files
+func effectiveDefaults() (*generator.ConfigFile, error) {
+	layers, err := generator.LoadDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &generator.ConfigFile{}
+	for _, layer := range layers {
+		merged.Merge(layer)
+	}
+	return merged, nil
+}
+
+func configGet(key string) error {
+	merged, err := effectiveDefaults()
+	if err != nil {
+		return err
+	}
+
+	value, err := merged.Get(key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func configSet(key, value string) error {
+	path, err := generator.UserConfigPath()
+	if err != nil {
+		return err
+	}
+
+	// Start from the existing user file, if any
+	cfg := &generator.ConfigFile{}
+	if _, err := os.Stat(path); err == nil {
+		if cfg, err = generator.LoadConfigFile(path); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// Store paths absolute so they do not depend on the config file location
+	if value != "" && generator.IsPathKey(key) {
+		if value, err = filepath.Abs(generator.ExpandHome(value)); err != nil {
+			return fmt.Errorf("invalid path: %v", err)
+		}
+	}
+
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+
+	if err := generator.SaveConfigFile(path, cfg); err != nil {
+		return err
+	}
+
+	if value == "" {
+		fmt.Printf("✓ Cleared %s in %s\n", key, path)
+	} else {
+		fmt.Printf("✓ Set %s = %s in %s\n", key, value, path)
+	}
+	return nil
+}
+
+func configList() error {
+	merged, err := effectiveDefaults()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(merged.Origins))
+	for key := range merged.Origins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		fmt.Println("No defaults configured. Set one with 'go-projo config set <key> <value>'.")
+		return nil
+	}
+
+	for _, key := range keys {
+		value, err := merged.Get(key)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s = %s    (%s)\n", key, value, merged.Origins[key])
+	}
+	return nil
+}
+
+func configPath() error {
+	path, err := generator.UserConfigPath()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user: %s\n", path)
+	if org := os.Getenv(generator.OrgConfigEnv); org != "" {
+		fmt.Printf("org:  %s\n", org)
+	} else {
+		fmt.Printf("org:  (not set; point $%s at a shared file)\n", generator.OrgConfigEnv)
+	}
+	return nil
+}
+
+func showConfigHelp() {
+	fmt.Printf(`Manage default values applied to every generated project
+
+Usage:
+  go-projo config <command> [arguments]
+
+Commands:
+  get <key>            Print the effective value of a key
+  set <key> <value>    Store a value in the user defaults file (an empty
+                       value clears it)
+  list                 Print every configured value and the file it comes from
+  path                 Print the locations of the defaults files
+
+Keys:
+  %s
+
+Defaults are read from an organization file ($%s) and the user file
+($%s, or go-projo/config.yaml under $XDG_CONFIG_HOME or ~/.config).
+They apply beneath project config files (-config) and flags, with the
+user file taking precedence over the organization file.
+
+Examples:
+  go-projo config set author "Jane Doe <jane@example.com>"
+  go-projo config set module_prefix github.com/ourorg
+  go-projo config set license Apache-2.0
+  go-projo config list
+`, strings.Join(generator.ConfigKeys(), ", "), generator.OrgConfigEnv, generator.UserConfigEnv)
+}
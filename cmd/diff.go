@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+func executeDiff() error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+
+	var paths listFlag
+	fs.Var(&paths, "path", "Comma-separated files, directories or glob patterns to compare (repeatable)")
+
+	var (
+		dir          = fs.String("dir", ".", "Directory of the project to compare")
+		templateDir  = fs.String("template-dir", "", "Template directory to use instead of the recorded one")
+		patch        = fs.String("patch", "", "Write the diff to a patch file ('-' for stdout) instead of printing a summary")
+		exitCode     = fs.Bool("exit-code", false, "Exit with status 1 when the project has drifted")
+		outputFormat = fs.String("output-format", formatText, "Output format: text, json")
+		help         = fs.Bool("help", false, "Show help message")
+	)
+
+	fs.Usage = func() {
+		showDiffHelp()
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+
+	if *help {
+		showDiffHelp()
+		return nil
+	}
+
+	if err := checkOutputFormat(*outputFormat); err != nil {
+		return err
+	}
+
+	gen, _, err := generator.OpenProject(*dir, *templateDir)
+	if err != nil {
+		return err
+	}
+
+	plan, err := gen.Diff()
+	if err != nil {
+		return fmt.Errorf("failed to render project: %v", err)
+	}
+
+	// Only files the templates produce are compared; the manifest itself
+	// always differs once the templates change
+	drifted := []generator.PlannedFile{}
+	for _, file := range plan.Files {
+		if file.Path == generator.ManifestFile || file.Status == generator.FileUnchanged {
+			continue
+		}
+		if len(paths) > 0 && !generator.MatchPath(paths, file.Path) {
+			continue
+		}
+		drifted = append(drifted, file)
+	}
+
+	switch {
+	case *outputFormat == formatJSON:
+		out := diffOutput{BasePath: plan.BasePath, Files: []diffFile{}}
+		for _, file := range drifted {
+			out.Files = append(out.Files, diffFile{file.Path, file.Status, file.Diff})
+		}
+		if err := writeJSON(out); err != nil {
+			return err
+		}
+	case *patch == "-":
+		fmt.Print(joinDiffs(drifted))
+	case *patch != "":
+		if err := os.WriteFile(*patch, []byte(joinDiffs(drifted)), 0644); err != nil {
+			return fmt.Errorf("failed to write patch: %v", err)
+		}
+		fmt.Printf("Wrote %d file diff(s) to %s\n", len(drifted), *patch)
+	default:
+		printDrift(plan.BasePath, drifted)
+	}
+
+	if *exitCode && len(drifted) > 0 {
+		return fmt.Errorf("%d file(s) differ from the templates", len(drifted))
+	}
+	return nil
+}
+
+// diffOutput is the document printed by diff -output-format json
+type diffOutput struct {
+	BasePath string     `json:"base_path"`
+	Files    []diffFile `json:"files"`
+}
+
+// diffFile is a file that differs from the template output; a status of
+// "new" means the file is missing from the project
+type diffFile struct {
+	Path   string               `json:"path"`
+	Status generator.FileStatus `json:"status"`
+	Diff   string               `json:"diff"`
+}
+
+// joinDiffs concatenates the unified diffs of files into one patch
+func joinDiffs(files []generator.PlannedFile) string {
+	var sb strings.Builder
+	for _, file := range files {
+		sb.WriteString(file.Diff)
+	}
+	return sb.String()
+}
+
+// printDrift lists drifted files followed by their diffs
+func printDrift(basePath string, files []generator.PlannedFile) {
+	if len(files) == 0 {
+		fmt.Printf("%s matches its templates\n", basePath)
+		return
+	}
+
+	fmt.Printf("%s differs from its templates in %d file(s):\n", basePath, len(files))
+	for _, file := range files {
+		marker, note := "~", "changed"
+		if file.Status == generator.FileNew {
+			marker, note = "+", "missing"
+		}
+		fmt.Printf("  %s %s (%s)\n", marker, file.Path, note)
+	}
+
+	for _, file := range files {
+		fmt.Println()
+		fmt.Print(file.Diff)
+	}
+}
+
+func showDiffHelp() {
+	fmt.Println(`Show how a generated project has drifted from its templates
+
+Renders the settings recorded in the project's .projo.json through the
+current templates and prints a unified diff from the files on disk to the
+template output. Files the templates do not produce are ignored.
+
+Usage:
+  go-projo diff [flags]
+
+Flags:
+  -dir string
+        Directory of the project to compare (default ".")
+  -path list
+        Comma-separated files, directories or glob patterns to limit the
+        comparison to (repeatable)
+  -patch string
+        Write the diff to a patch file instead of printing a summary; use
+        "-" for stdout. Apply it with 'git apply' to match the templates
+  -template-dir string
+        Template directory to use instead of the one recorded in the
+        manifest
+  -exit-code
+        Exit with status 1 when the project has drifted
+  -output-format string
+        Output format: text, json (default "text")
+  -help
+        Show this help message
+
+Examples:
+  # Show drift of the project in the current directory
+  go-projo diff
+
+  # Only compare the Makefile and the Kubernetes manifests
+  go-projo diff -dir ./mysvc -path Makefile,deployments/k8s
+
+  # Save a patch that brings the project back in line
+  go-projo diff -dir ./mysvc -patch drift.patch
+
+  # Fail a CI job when a service has drifted
+  go-projo diff -exit-code`)
+}
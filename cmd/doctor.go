@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+func executeDoctor() error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+
+	var (
+		dir          = fs.String("dir", ".", "Directory of the project to check")
+		projectType  = fs.String("type", string(generator.ProjectTypeAPI), "Project type to check against when there is no .projo.json: "+strings.Join(generator.TypeNames(), ", "))
+		failOn       = fs.String("fail-on", string(generator.SeverityError), "Lowest severity that makes doctor exit non-zero: error, warning, info")
+		outputFormat = fs.String("output-format", formatText, "Output format: text, json")
+		help         = fs.Bool("help", false, "Show help message")
+	)
+
+	fs.Usage = func() {
+		showDoctorHelp()
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+
+	if *help {
+		showDoctorHelp()
+		return nil
+	}
+
+	if err := checkOutputFormat(*outputFormat); err != nil {
+		return err
+	}
+
+	threshold, err := generator.ParseSeverity(*failOn)
+	if err != nil {
+		return err
+	}
+
+	pType, err := generator.ParseProjectType(*projectType)
+	if err != nil {
+		return err
+	}
+
+	gen, err := generator.InspectProject(*dir, pType)
+	if err != nil {
+		return err
+	}
+
+	report, err := gen.Doctor()
+	if err != nil {
+		return fmt.Errorf("failed to check project: %v", err)
+	}
+
+	if *outputFormat == formatJSON {
+		if err := writeJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(report)
+	}
+
+	if report.Failed(threshold) {
+		return fmt.Errorf("project does not conform to the %s layout", report.Type)
+	}
+	return nil
+}
+
+// printDoctorReport prints findings grouped by severity
+func printDoctorReport(report *generator.DoctorReport) {
+	fmt.Printf("Checking %s (%s)\n", report.BasePath, report.Type)
+
+	if len(report.Findings) == 0 {
+		fmt.Println("\n✓ No problems found")
+		return
+	}
+
+	fmt.Println()
+	for _, severity := range []generator.Severity{generator.SeverityError, generator.SeverityWarning, generator.SeverityInfo} {
+		for _, f := range report.Findings {
+			if f.Severity == severity {
+				fmt.Printf("  %-7s [%s] %s\n", f.Severity, f.Check, f.Message)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d error(s), %d warning(s), %d info\n",
+		report.Count(generator.SeverityError),
+		report.Count(generator.SeverityWarning),
+		report.Count(generator.SeverityInfo))
+}
+
+func showDoctorHelp() {
+	fmt.Printf(`Check that a project conforms to the layout of its project type
+
+Compares the directory with what go-projo generates for it: directories,
+required files, the module path and Go version in go.mod, and the
+Makefile targets. Settings come from the project's .projo.json; projects
+without one are checked as -type using the module path in go.mod.
+
+Missing Go sources, go.mod or Makefile and a wrong module path are errors;
+missing directories, other files and Makefile targets are warnings.
+
+Usage:
+  go-projo doctor [flags]
+
+Flags:
+  -dir string
+        Directory of the project to check (default ".")
+  -type string
+        Project type to check against when there is no .projo.json:
+        %s (default "api")
+  -fail-on string
+        Lowest severity that makes doctor exit non-zero: error, warning,
+        info (default "error")
+  -output-format string
+        Output format: text, json (default "text")
+  -help
+        Show this help message
+
+Examples:
+  # Check the project in the current directory
+  go-projo doctor
+
+  # Check a service that predates go-projo, failing on warnings too
+  go-projo doctor -dir ./legacy-svc -type microservice -fail-on warning
+
+  # Collect findings in CI
+  go-projo doctor -output-format json > doctor.json`+"\n", strings.Join(generator.TypeNames(), ", "))
+}
@@ -1,27 +1,56 @@
 package cmd
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/yogabagas/gen-projo/generator"
 )
 
+// modulePrefixEnv names the environment variable holding a default module prefix
+const modulePrefixEnv = "GO_PROJO_MODULE_PREFIX"
+
 func executeGenerate() error {
 	// Create a new FlagSet for the generate command
 	fs := flag.NewFlagSet("gen", flag.ExitOnError)
 
+	// Flags are bound straight onto a ConfigFile so that only the flags
+	// actually passed override values from a -config file
+	fromFlags := &generator.ConfigFile{Vars: map[string]string{}, Source: "flag"}
+	fs.StringVar(&fromFlags.Name, "name", "", "Project name (required)")
+	fs.StringVar(&fromFlags.Module, "module", "", "Go module path (required)")
+	fs.StringVar(&fromFlags.Type, "type", "", "Project type: "+strings.Join(generator.TypeNames(), ", "))
+	fs.StringVar(&fromFlags.Description, "desc", "", "Project description")
+	fs.StringVar(&fromFlags.Author, "author", "", "Author name")
+	fs.StringVar(&fromFlags.License, "license", "", "License named in the README")
+	fs.StringVar(&fromFlags.GoVersion, "go-version", "", "Go version")
+	fs.StringVar(&fromFlags.ModulePrefix, "module-prefix", "", "Derive the module path as <prefix>/<name> when -module is not given")
+	fs.StringVar(&fromFlags.Output, "output", "", "Output directory path")
+	fs.StringVar(&fromFlags.OnConflict, "on-conflict", "", "What to do with existing files: fail, skip, overwrite, prompt, merge")
+	fs.StringVar(&fromFlags.GoCheck, "go-check", "", "What to do with invalid generated Go code: error, warn, off")
+	fs.StringVar(&fromFlags.TemplateDir, "template-dir", "", "Load the project type from a local template directory")
+	fs.StringVar(&fromFlags.OverrideDir, "override-dir", "", "Directory of files replacing or adding entries of the project type")
+	fs.Var(varsFlag(fromFlags.Vars), "var", "Template variable as name=value (repeatable)")
+	fs.Var((*listFlag)(&fromFlags.Exclude), "exclude", "Comma-separated files or directories to leave out (repeatable)")
+	fs.Var((*listFlag)(&fromFlags.Features), "with", "Comma-separated features to add: "+strings.Join(generator.FeatureNames(), ", ")+" (repeatable)")
+	fs.Var((*listFlag)(&fromFlags.Hooks), "hooks", "Comma-separated built-in hooks to run: gofmt, goimports, tidy, git, or none")
+
 	var (
-		name        = fs.String("name", "", "Project name (required)")
-		module      = fs.String("module", "", "Go module path (required)")
-		projectType = fs.String("type", "api", "Project type: api, cli, microservice, library")
-		description = fs.String("desc", "", "Project description")
-		author      = fs.String("author", "", "Author name")
-		goVersion   = fs.String("go-version", "1.24", "Go version")
-		outputPath  = fs.String("output", ".", "Output directory path")
-		help        = fs.Bool("help", false, "Show help message")
+		configPath   = fs.String("config", "", "Read the project definition from a YAML or JSON file")
+		dryRun       = fs.Bool("dry-run", false, "Show what would be generated without writing anything")
+		showDiff     = fs.Bool("diff", false, "With -dry-run, print a unified diff for every file")
+		noHooks      = fs.Bool("no-hooks", false, "Skip every post-generation hook")
+		archive      = fs.String("archive", "", "Write the project to a .tar.gz or .zip archive instead of a directory ('-' for stdout)")
+		archiveFmt   = fs.String("archive-format", "", "Archive format: tar.gz, zip (default from the -archive extension, tar.gz for stdout)")
+		yes          = fs.Bool("yes", false, "Generate without asking for confirmation")
+		wizard       = fs.Bool("interactive", false, "Walk through every setting with prompts")
+		outputFormat = fs.String("output-format", formatText, "Output format: text, json")
+		help         = fs.Bool("help", false, "Show help message")
 	)
 
 	// Custom usage function
@@ -40,49 +69,63 @@ func executeGenerate() error {
 		return nil
 	}
 
-	// Validate required flags
-	if *name == "" {
-		return fmt.Errorf("-name is required\n\nRun 'go-projo gen -help' for usage")
+	if err := checkOutputFormat(*outputFormat); err != nil {
+		return err
 	}
 
-	if *module == "" {
-		return fmt.Errorf("-module is required\n\nRun 'go-projo gen -help' for usage")
+	// Everything up to creating the generator can fail on bad settings;
+	// report those in the requested format too
+	var config generator.ProjectConfig
+	fail := func(err error) error {
+		return failGenerate(*outputFormat, config, err)
 	}
 
-	// Validate project type
-	var pType generator.ProjectType
-	switch *projectType {
-	case "api":
-		pType = generator.ProjectTypeAPI
-	case "cli":
-		pType = generator.ProjectTypeCLI
-	case "microservice", "micro":
-		pType = generator.ProjectTypeMicro
-	case "library", "lib":
-		pType = generator.ProjectTypeLibrary
-	default:
-		return fmt.Errorf("invalid project type '%s'. Must be one of: api, cli, microservice, library", *projectType)
+	settings, err := layerSettings(*configPath, fromFlags)
+	if err != nil {
+		return fail(err)
 	}
 
-	// Get absolute output path
-	absOutputPath, err := filepath.Abs(*outputPath)
-	if err != nil {
-		return fmt.Errorf("invalid output path: %v", err)
+	// Run the wizard when asked to, or when started bare on a terminal
+	*wizard = *wizard || (fs.NFlag() == 0 && stdinIsTerminal())
+	if *wizard {
+		if *outputFormat == formatJSON {
+			return fail(fmt.Errorf("-interactive cannot be combined with -output-format json"))
+		}
+		if *archive == "-" {
+			return fmt.Errorf("-interactive cannot be combined with -archive -")
+		}
+		if err := runWizard(settings); err != nil {
+			return err
+		}
 	}
 
-	// Create generator config
-	config := generator.ProjectConfig{
-		Name:        *name,
-		Module:      *module,
-		Type:        pType,
-		Description: *description,
-		Author:      *author,
-		GoVersion:   *goVersion,
-		OutputPath:  absOutputPath,
+	config, err = buildProjectConfig(settings)
+	if err != nil {
+		return fail(err)
 	}
 
 	// Create generator
-	gen := generator.NewGenerator(config)
+	gen, err := generator.NewGenerator(config)
+	if err != nil {
+		return fail(err)
+	}
+
+	if *archive != "" {
+		if *dryRun {
+			return fail(fmt.Errorf("-dry-run cannot be combined with -archive"))
+		}
+		return generateArchive(gen, *archive, *archiveFmt, *outputFormat)
+	}
+
+	if *outputFormat == formatJSON {
+		return generateJSON(gen, *dryRun, *showDiff, !*noHooks)
+	}
+
+	// Only ask questions when someone can answer them
+	interactive := !*yes && (*wizard || stdinIsTerminal())
+	if interactive {
+		gen.SetConflictPrompter(promptOverwrite)
+	}
 
 	// Show project info
 	fmt.Println("=== Go Project Generator ===")
@@ -90,60 +133,483 @@ func executeGenerate() error {
 	fmt.Print(gen.GetProjectInfo())
 	fmt.Println()
 
-	// Confirm generation
-	fmt.Print("Generate project? (y/n): ")
-	var confirm string
-	fmt.Scanln(&confirm)
+	// Print the plan and stop if this is a dry run
+	if *dryRun {
+		plan, err := gen.Plan()
+		if err != nil {
+			return fmt.Errorf("failed to plan project: %v", err)
+		}
+		printPlan(plan, *showDiff)
+		printWarnings(plan.Warnings)
+		fmt.Println("\nDry run: no files were written")
+		return nil
+	}
 
-	if confirm != "y" && confirm != "Y" {
+	// Confirm generation
+	if interactive && !confirm("Generate project?") {
 		fmt.Println("Generation cancelled")
 		return nil
 	}
 
 	// Generate project
 	fmt.Println("\nGenerating project...")
-	if err := gen.Generate(); err != nil {
+	result, err := gen.Generate()
+	if err != nil {
 		return fmt.Errorf("failed to generate project: %v", err)
 	}
 
 	fmt.Println("✓ Project generated successfully!")
+	printResult(result)
+	printWarnings(result.Warnings)
+
+	var hooks []generator.HookResult
+	if !*noHooks {
+		fmt.Println("\nRunning hooks...")
+		hooks = gen.RunHooks(result)
+		printHooks(hooks)
+	}
+
 	fmt.Printf("\nNext steps:\n")
-	fmt.Printf("  cd %s\n", filepath.Join(absOutputPath, *name))
-	fmt.Printf("  go mod tidy\n")
+	fmt.Printf("  cd %s\n", result.BasePath)
+	if work, _ := generator.FindWorkspace(config.OutputPath); work != "" {
+		fmt.Printf("  go work use .    # add to %s\n", work)
+	}
+	if !hookSucceeded(hooks, generator.HookModTidy) {
+		fmt.Printf("  go mod tidy\n")
+	}
 	fmt.Printf("  make build\n")
 
 	return nil
 }
 
+// generateOutput is the document printed by -output-format json
+type generateOutput struct {
+	Project generator.ProjectConfig `json:"project"`
+	DryRun  bool                    `json:"dry_run"`
+	Plan    *generator.Plan         `json:"plan,omitempty"`
+	Result  *generator.Result       `json:"result,omitempty"`
+	Hooks   []generator.HookResult  `json:"hooks,omitempty"`
+	Archive string                  `json:"archive,omitempty"`
+	Errors  []string                `json:"errors,omitempty"`
+}
+
+// generateJSON plans and, unless this is a dry run, generates the project
+// and runs its hooks without prompting, then prints everything as a single
+// JSON document. The document is printed even when generation fails so
+// tools can read the errors.
+func generateJSON(gen *generator.Generator, dryRun, showDiff, runHooks bool) error {
+	out := generateOutput{
+		Project: gen.Config(),
+		DryRun:  dryRun,
+	}
+
+	err := func() error {
+		plan, err := gen.Plan()
+		if err != nil {
+			return fmt.Errorf("failed to plan project: %v", err)
+		}
+		if !showDiff {
+			for i := range plan.Files {
+				plan.Files[i].Diff = ""
+			}
+		}
+		out.Plan = plan
+
+		if dryRun {
+			return nil
+		}
+
+		result, err := gen.Generate()
+		if err != nil {
+			return fmt.Errorf("failed to generate project: %v", err)
+		}
+		out.Result = result
+
+		if runHooks {
+			out.Hooks = gen.RunHooks(result)
+		}
+		return nil
+	}()
+	if err != nil {
+		out.Errors = append(out.Errors, err.Error())
+	}
+
+	if werr := writeJSON(out); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// failGenerate reports an error raised before anything was generated. With
+// JSON output it prints the same document as a failed generation, with
+// every problem of a validation error listed separately.
+func failGenerate(outputFormat string, project generator.ProjectConfig, err error) error {
+	if outputFormat != formatJSON {
+		return err
+	}
+
+	out := generateOutput{Project: project}
+	var invalid *generator.ValidationError
+	if errors.As(err, &invalid) {
+		for _, p := range invalid.Problems {
+			out.Errors = append(out.Errors, p.Error())
+		}
+	} else {
+		out.Errors = append(out.Errors, err.Error())
+	}
+
+	if werr := writeJSON(out); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// generateArchive writes the project to an archive file, or to stdout when
+// path is "-", instead of the output directory. Hooks need a directory and
+// are not run.
+func generateArchive(gen *generator.Generator, path, formatName, outputFormat string) error {
+	var (
+		format generator.ArchiveFormat
+		err    error
+	)
+	switch {
+	case formatName != "":
+		format, err = generator.ParseArchiveFormat(formatName)
+	case path == "-":
+		format = generator.ArchiveTarGz
+	default:
+		format, err = generator.ArchiveFormatFor(path)
+	}
+	if err != nil {
+		return failGenerate(outputFormat, gen.Config(), err)
+	}
+
+	root := gen.Config().Name
+	if path == "-" {
+		if outputFormat == formatJSON {
+			return failGenerate(outputFormat, gen.Config(), fmt.Errorf("-archive - cannot be combined with -output-format json"))
+		}
+		if _, err := gen.Write(generator.NewArchiveSink(os.Stdout, format, root)); err != nil {
+			return fmt.Errorf("failed to generate project: %v", err)
+		}
+		return nil
+	}
+
+	// Write next to the destination and rename, so a failure never leaves
+	// a truncated archive behind. The file is opened with mode 0644 less the
+	// umask, as os.CreateTemp would leave the archive readable only by us.
+	tmpName := filepath.Join(filepath.Dir(path), fmt.Sprintf(".projo-archive-%d-%d", os.Getpid(), time.Now().UnixNano()))
+	tmp, err := os.OpenFile(tmpName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return failGenerate(outputFormat, gen.Config(), fmt.Errorf("failed to create archive: %v", err))
+	}
+	defer os.Remove(tmp.Name())
+
+	result, err := gen.Write(generator.NewArchiveSink(tmp, format, root))
+	if cerr := tmp.Close(); err == nil && cerr != nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to generate project: %v", err)
+	}
+
+	if outputFormat == formatJSON {
+		out := generateOutput{Project: gen.Config(), Result: result, Archive: path}
+		if err != nil {
+			out.Errors = append(out.Errors, err.Error())
+		}
+		if werr := writeJSON(out); werr != nil {
+			return werr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Wrote %d files to %s\n", len(result.Created), path)
+	printWarnings(result.Warnings)
+	return nil
+}
+
+// layerSettings merges built-in defaults, $GO_PROJO_MODULE_PREFIX, the
+// organization and user defaults files, the project config file at
+// configPath (if any) and flags, in increasing precedence
+func layerSettings(configPath string, fromFlags *generator.ConfigFile) (*generator.ConfigFile, error) {
+	settings := &generator.ConfigFile{
+		Type:       string(generator.ProjectTypeAPI),
+		Output:     ".",
+		OnConflict: string(generator.ConflictFail),
+		GoCheck:    string(generator.GoCheckError),
+		Source:     generator.OriginDefault,
+	}
+	settings.Merge(&generator.ConfigFile{
+		ModulePrefix: os.Getenv(modulePrefixEnv),
+		Source:       "$" + modulePrefixEnv,
+	})
+
+	defaults, err := generator.LoadDefaults()
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range defaults {
+		settings.Merge(layer)
+	}
+
+	if configPath != "" {
+		file, err := generator.LoadConfigFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		settings.Merge(file)
+	}
+
+	settings.Merge(fromFlags)
+	return settings, nil
+}
+
+// buildProjectConfig validates layered settings and converts them into a
+// generator.ProjectConfig
+func buildProjectConfig(settings *generator.ConfigFile) (generator.ProjectConfig, error) {
+	// Validate required values
+	if settings.Name == "" {
+		return generator.ProjectConfig{}, fmt.Errorf("-name is required\n\nRun 'go-projo gen -help' for usage")
+	}
+
+	// Validate project type (a template directory brings its own)
+	var pType generator.ProjectType
+	if settings.TemplateDir == "" {
+		t, err := generator.ParseProjectType(settings.Type)
+		if err != nil {
+			return generator.ProjectConfig{}, err
+		}
+		pType = t
+	}
+
+	conflictPolicy, err := generator.ParseConflictPolicy(settings.OnConflict)
+	if err != nil {
+		return generator.ProjectConfig{}, err
+	}
+
+	goCheck, err := generator.ParseGoCheckMode(settings.GoCheck)
+	if err != nil {
+		return generator.ProjectConfig{}, err
+	}
+
+	features, err := generator.ParseFeatures(settings.Features)
+	if err != nil {
+		return generator.ProjectConfig{}, err
+	}
+
+	hooks, err := generator.ParseHooks(settings.Hooks)
+	if err != nil {
+		return generator.ProjectConfig{}, err
+	}
+
+	// Resolve paths given on the command line against the working directory
+	paths := []struct {
+		value *string
+		what  string
+	}{
+		{&settings.Output, "output path"},
+		{&settings.TemplateDir, "template directory"},
+		{&settings.OverrideDir, "override directory"},
+	}
+	for _, p := range paths {
+		if *p.value == "" {
+			continue
+		}
+		abs, err := filepath.Abs(generator.ExpandHome(*p.value))
+		if err != nil {
+			return generator.ProjectConfig{}, fmt.Errorf("invalid %s: %v", p.what, err)
+		}
+		*p.value = abs
+	}
+
+	config := generator.ProjectConfig{
+		Name:        settings.Name,
+		Module:      settings.Module,
+		Type:        pType,
+		Description: settings.Description,
+		Author:      settings.Author,
+		License:     settings.License,
+		GoVersion:   settings.GoVersion,
+		OutputPath:  settings.Output,
+		OnConflict:  conflictPolicy,
+		GoCheck:     goCheck,
+		TemplateDir: settings.TemplateDir,
+		Vars:        settings.Vars,
+		OverrideDir: settings.OverrideDir,
+		Exclude:     settings.Exclude,
+		Features:    features,
+		Hooks:       hooks,
+		Origins:     settings.Origins,
+	}
+
+	// Fill in author, module and Go version from the environment
+	generator.InferDefaults(&config, settings.ModulePrefix)
+
+	if config.Module == "" {
+		return generator.ProjectConfig{}, fmt.Errorf("-module is required (or set -module-prefix or $%s)\n\nRun 'go-projo gen -help' for usage", modulePrefixEnv)
+	}
+
+	return config, nil
+}
+
+// promptOverwrite asks on stdin whether an existing file may be overwritten
+func promptOverwrite(path string) (bool, error) {
+	return confirm(fmt.Sprintf("%s already exists. Overwrite?", path)), nil
+}
+
+// printResult prints which files were created, skipped or overwritten
+func printResult(result *generator.Result) {
+	sections := []struct {
+		title string
+		paths []string
+	}{
+		{"Created", result.Created},
+		{"Overwritten", result.Overwritten},
+		{"Merged", result.Merged},
+		{"Merged with conflicts (resolve the markers by hand)", result.Conflicted},
+		{"Skipped", result.Skipped},
+		{"Unchanged", result.Unchanged},
+	}
+
+	for _, section := range sections {
+		if len(section.paths) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d):\n", section.title, len(section.paths))
+		for _, p := range section.paths {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+}
+
+// printWarnings lists invalid Go files written under -go-check warn
+func printWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Printf("\nWarnings: generated Go code is not valid (%d):\n", len(warnings))
+	for _, w := range warnings {
+		fmt.Printf("  %s\n", w)
+	}
+}
+
+// printHooks prints the outcome of every post-generation hook
+func printHooks(hooks []generator.HookResult) {
+	for _, h := range hooks {
+		switch h.Status {
+		case generator.HookOK:
+			fmt.Printf("  ✓ %s\n", h.Name)
+		case generator.HookSkipped:
+			fmt.Printf("  - %s (skipped: %s)\n", h.Name, h.Output)
+		default:
+			fmt.Printf("  ✗ %s failed: %s\n", h.Name, h.Command)
+			for _, line := range strings.Split(h.Output, "\n") {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+	}
+}
+
+// hookSucceeded reports whether the named hook ran successfully
+func hookSucceeded(hooks []generator.HookResult, name string) bool {
+	for _, h := range hooks {
+		if h.Name == name {
+			return h.Status == generator.HookOK
+		}
+	}
+	return false
+}
+
 func showGenerateHelp() {
-	fmt.Println(`Generate a new Go project structure
+	fmt.Printf(`Generate a new Go project structure
 
 Usage:
   go-projo gen [flags]
 
 Flags:
+  -config string
+        Read the project definition from a YAML or JSON file; flags given
+        on the command line override values from the file
   -name string
         Project name (required)
   -module string
-        Go module path (required)
+        Go module path (required unless a module prefix is set)
+  -module-prefix string
+        Derive the module path as <prefix>/<name> when -module is not
+        given (default $GO_PROJO_MODULE_PREFIX)
   -type string
-        Project type: api, cli, microservice, library (default "api")
+        Project type: %s (default "api")
   -desc string
         Project description
   -author string
-        Author name
+        Author name (default from git config user.name and user.email)
+  -license string
+        License named in the generated README (default "MIT")
   -go-version string
-        Go version (default "1.24")
+        Go version (default from a parent go.work, then the installed Go
+        toolchain, then "1.24")
   -output string
         Output directory path (default ".")
+  -template-dir string
+        Load the project type from a local template directory instead of
+        the built-in types (-type is ignored)
+  -with list
+        Comma-separated features to add to the project type; features
+        they require are added too (repeatable, see Features below)
+  -var name=value
+        Set a template variable, available as {{.Vars.name}} (repeatable)
+  -override-dir string
+        Directory of files that replace or add individual entries of the
+        project type, using the same relative paths (a .tmpl suffix is dropped)
+  -exclude list
+        Comma-separated files, directories or glob patterns to leave out
+        of the project type (repeatable)
+  -on-conflict string
+        What to do with files that already exist: fail, skip, overwrite,
+        prompt, merge (default "fail")
+  -go-check string
+        What to do when a generated .go file is not valid Go: error
+        (abort before writing), warn (write it unformatted), off
+        (default "error")
+  -hooks list
+        Comma-separated built-in hooks run after generation: gofmt,
+        goimports, tidy, git, or none (default gofmt)
+  -no-hooks
+        Skip every post-generation hook, including template commands
+  -archive string
+        Write the project to a .tar.gz or .zip archive instead of the
+        output directory; "-" streams it to stdout. Hooks are not run
+  -archive-format string
+        Archive format: tar.gz, zip (default from the -archive extension,
+        tar.gz for stdout)
+  -interactive
+        Walk through every setting with prompts, defaults and validation
+        (automatic when gen is run with no flags on a terminal)
+  -yes
+        Generate without asking for confirmation (implied when stdin is
+        not a terminal)
+  -output-format string
+        Output format: text, json. JSON output never prompts and prints
+        the plan and the result as one document (default "text")
+  -dry-run
+        Show the file tree that would be generated without writing anything
+  -diff
+        With -dry-run, print a unified diff of every file against what exists on disk
   -help
         Show this help message
 
 Project Types:
-  api           REST API server with HTTP handlers
-  cli           Command-line tool
-  microservice  Microservice with HTTP/gRPC and Docker/K8s configs
-  library       Reusable Go library package
+%s
+
+Features:
+%s
 
 Examples:
   # Generate REST API project
@@ -155,9 +621,43 @@ Examples:
   # Generate microservice project with description
   go-projo gen -name myservice -module github.com/user/myservice -type microservice -desc "My awesome service"
 
+  # Generate an API with PostgreSQL, migrations and tracing
+  go-projo gen -name myapi -module github.com/user/myapi -type api -with postgres,migrate,otel
+
   # Generate library project
   go-projo gen -name mylib -module github.com/user/mylib -type library -author "Your Name"
 
   # Generate to a specific directory
-  go-projo gen -name myapi -module github.com/user/myapi -output ~/projects`)
+  go-projo gen -name myapi -module github.com/user/myapi -output ~/projects
+
+  # Generate from your own template directory
+  go-projo gen -name myapi -module github.com/user/myapi -template-dir ./our-templates -var Port=9000
+
+  # Use our own middleware and Dockerfile, and drop the API docs
+  go-projo gen -name mysvc -module github.com/user/mysvc -type microservice -override-dir ./overrides -exclude docs/API.md
+
+  # Add missing files to an existing project without touching the others
+  go-projo gen -name myapi -module github.com/user/myapi -on-conflict skip
+
+  # Generate from a version-controlled project definition
+  go-projo gen -config projo.yaml
+
+  # Let go-projo ask for everything
+  go-projo gen -interactive
+
+  # Generate from a script or CI and read the result as JSON
+  go-projo gen -config projo.yaml -yes -output-format json
+
+  # Package a project without touching the output directory
+  go-projo gen -name myapi -module github.com/user/myapi -archive myapi.zip
+  go-projo gen -name myapi -module github.com/user/myapi -archive - | tar xz -C /tmp
+
+  # Also fetch dependencies and create a git repository with an initial commit
+  go-projo gen -name myapi -module github.com/user/myapi -hooks gofmt,tidy,git
+
+  # Generate without formatting or template commands
+  go-projo gen -name myapi -module github.com/user/myapi -no-hooks
+
+  # Preview the generated files and their contents without writing them
+  go-projo gen -name myapi -module github.com/user/myapi -dry-run -diff`+"\n", strings.Join(generator.TypeNames(), ", "), generator.TypeHelp("  "), generator.FeatureHelp("  "))
 }
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+func TestLayerSettings(t *testing.T) {
+	type want struct {
+		value  string
+		origin string
+	}
+
+	tests := []struct {
+		name string
+		env  string
+		// org, user and config are YAML files; empty means absent
+		org    string
+		user   string
+		config string
+		flags  map[string]string
+		// want is keyed by config key; origins "org", "user" and "config"
+		// stand for the path of that file
+		want map[string]want
+	}{
+		{
+			name: "built-in defaults",
+			want: map[string]want{
+				"type":          {"api", ""},
+				"output":        {".", ""},
+				"on_conflict":   {"fail", ""},
+				"go_check":      {"error", ""},
+				"module_prefix": {"", ""},
+			},
+		},
+		{
+			name: "environment over defaults",
+			env:  "github.com/env",
+			want: map[string]want{
+				"module_prefix": {"github.com/env", "$" + modulePrefixEnv},
+			},
+		},
+		{
+			name: "org defaults over environment",
+			env:  "github.com/env",
+			org:  "module_prefix: github.com/org\ngo_check: warn\n",
+			want: map[string]want{
+				"module_prefix": {"github.com/org", "org"},
+				"go_check":      {"warn", "org"},
+				"type":          {"api", ""},
+			},
+		},
+		{
+			name: "user defaults over org defaults",
+			org:  "license: Apache-2.0\nauthor: Org\n",
+			user: "license: BSD-3-Clause\n",
+			want: map[string]want{
+				"license": {"BSD-3-Clause", "user"},
+				"author":  {"Org", "org"},
+			},
+		},
+		{
+			name:   "config file over user defaults",
+			user:   "author: User\ntype: cli\nvars:\n  A: user\n  B: user\n",
+			config: "author: Config\nvars:\n  B: config\n",
+			want: map[string]want{
+				"author": {"Config", "config"},
+				"type":   {"cli", "user"},
+				"vars.A": {"user", "user"},
+				"vars.B": {"config", "config"},
+			},
+		},
+		{
+			name:   "flags over everything",
+			env:    "github.com/env",
+			org:    "module_prefix: github.com/org\ndescription: org\n",
+			user:   "description: user\n",
+			config: "description: config\nhooks: [gofmt, tidy]\n",
+			flags: map[string]string{
+				"description":   "flag",
+				"module_prefix": "github.com/flag",
+				"hooks":         "git",
+			},
+			want: map[string]want{
+				"description":   {"flag", "flag"},
+				"module_prefix": {"github.com/flag", "flag"},
+				"hooks":         {"git", "flag"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			paths := map[string]string{}
+			write := func(name, content string) string {
+				if content == "" {
+					return ""
+				}
+				p := filepath.Join(dir, name+".yaml")
+				if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+				paths[name] = p
+				return p
+			}
+
+			t.Setenv(modulePrefixEnv, tt.env)
+			t.Setenv(generator.OrgConfigEnv, write("org", tt.org))
+			// An unset user file still has to point somewhere harmless
+			userPath := write("user", tt.user)
+			if userPath == "" {
+				userPath = filepath.Join(dir, "missing.yaml")
+			}
+			t.Setenv(generator.UserConfigEnv, userPath)
+			configPath := write("config", tt.config)
+
+			flags := &generator.ConfigFile{Source: "flag"}
+			for key, value := range tt.flags {
+				if err := flags.Set(key, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			settings, err := layerSettings(configPath, flags)
+			if err != nil {
+				t.Fatalf("layerSettings() error = %v", err)
+			}
+
+			for key, w := range tt.want {
+				got, err := settings.Get(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != w.value {
+					t.Errorf("%s = %q, want %q", key, got, w.value)
+				}
+				origin := w.origin
+				if p, ok := paths[origin]; ok {
+					origin = p
+				}
+				if settings.Origins[key] != origin {
+					t.Errorf("origin of %s = %q, want %q", key, settings.Origins[key], origin)
+				}
+			}
+		})
+	}
+}
+
+// newArchiveGenerator creates a library generator for archive tests
+func newArchiveGenerator(t *testing.T) *generator.Generator {
+	t.Helper()
+	gen, err := generator.New(generator.ProjectConfig{
+		Name:       "mylib",
+		Module:     "example.com/mylib",
+		Type:       generator.ProjectTypeLibrary,
+		OutputPath: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gen
+}
+
+// tarNames lists the entries of a .tar.gz stream
+func tarNames(t *testing.T, r io.Reader) []string {
+	t.Helper()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("not a gzip stream: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestGenerateArchiveFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mylib.tar.gz")
+	if err := generateArchive(newArchiveGenerator(t), path, "", formatText); err != nil {
+		t.Fatalf("generateArchive() error = %v", err)
+	}
+
+	// The archive gets the mode of any file created with 0644, not the
+	// private mode of a temporary file
+	reference := filepath.Join(dir, "reference")
+	if err := os.WriteFile(reference, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refInfo, err := os.Stat(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != refInfo.Mode().Perm() {
+		t.Errorf("archive mode = %v, want %v", info.Mode().Perm(), refInfo.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("generateArchive() left temporary files behind: %v", entries)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	names := tarNames(t, f)
+	if len(names) == 0 || names[0] != "mylib/" {
+		t.Errorf("archive entries = %v, want them under mylib/", names)
+	}
+}
+
+func TestGenerateArchiveStdout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	var buf bytes.Buffer
+	done := make(chan error)
+	go func() {
+		_, err := io.Copy(&buf, r)
+		done <- err
+	}()
+
+	gen := newArchiveGenerator(t)
+	err = generateArchive(gen, "-", "", formatText)
+	w.Close()
+	if cerr := <-done; cerr != nil {
+		t.Fatal(cerr)
+	}
+	if err != nil {
+		t.Fatalf("generateArchive() error = %v", err)
+	}
+
+	names := tarNames(t, &buf)
+	want := "mylib/" + generator.ManifestFile
+	found := false
+	for _, name := range names {
+		found = found || name == want
+	}
+	if !found {
+		t.Errorf("streamed archive entries = %v, want %s among them", names, want)
+	}
+
+	// Nothing is written to the output directory
+	if _, err := os.Stat(filepath.Join(gen.Config().OutputPath, "mylib")); !os.IsNotExist(err) {
+		t.Errorf("streaming an archive created the project directory: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+// starterConfig is the commented project definition written by
+// init-config; %s is replaced with the registered project types
+const starterConfig = `# go-projo project definition
+#
+# Generate the project with:
+#   go-projo gen -config projo.yaml
+#
+# Any flag passed to 'go-projo gen' overrides the value in this file.
+# Relative paths are resolved against the directory containing this file.
+
+# Project name, used for the output directory and binary (required)
+name: myapi
+
+# Go module path; may be left out when module_prefix is set
+module: github.com/user/myapi
+
+# Derive the module path as <module_prefix>/<name> when module is empty
+# module_prefix: github.com/user
+
+# Project type: %s
+type: api
+
+# Project description
+description: ""
+
+# Author; defaults to git config user.name and user.email
+# author: ""
+
+# License named in the README (default MIT)
+# license: MIT
+
+# Go version written to go.mod; defaults to the go directive of a parent
+# go.work, then the installed Go toolchain
+# go_version: "1.24"
+
+# Directory the project directory is created in
+output: .
+
+# What to do with files that already exist:
+# fail, skip, overwrite, prompt, merge
+on_conflict: fail
+
+# What to do when a generated .go file is not valid Go: error, warn, off
+# go_check: error
+
+# Load the project type from a local template directory instead of the
+# built-in types (type is ignored when this is set)
+# template_dir: ./our-templates
+
+# Directory of files that replace or add individual files of the project type
+# override_dir: ./overrides
+
+# Files, directories or glob patterns to leave out
+# exclude:
+#   - docs/API.md
+
+# Features added to the project type: %s
+# features:
+#   - postgres
+#   - migrate
+
+# Built-in hooks run after generation: gofmt, goimports, tidy, git, or none
+# (default gofmt)
+# hooks:
+#   - gofmt
+#   - tidy
+
+# Template variables, available as {{.Vars.Name}}
+# vars:
+#   Port: "8080"
+`
+
+func executeInitConfig() error {
+	fs := flag.NewFlagSet("init-config", flag.ExitOnError)
+
+	var (
+		output = fs.String("output", generator.DefaultConfigFile, "Path of the config file to write")
+		force  = fs.Bool("force", false, "Overwrite an existing file")
+		help   = fs.Bool("help", false, "Show help message")
+	)
+
+	fs.Usage = func() {
+		showInitConfigHelp()
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+
+	if *help {
+		showInitConfigHelp()
+		return nil
+	}
+
+	if !*force {
+		if _, err := os.Stat(*output); err == nil {
+			return fmt.Errorf("%s already exists (use -force to overwrite it)", *output)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(*output, []byte(fmt.Sprintf(starterConfig, strings.Join(generator.TypeNames(), ", "), strings.Join(generator.FeatureNames(), ", "))), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+
+	fmt.Printf("✓ Wrote %s\n", *output)
+	fmt.Printf("\nEdit it, then run:\n")
+	fmt.Printf("  go-projo gen -config %s\n", *output)
+
+	return nil
+}
+
+func showInitConfigHelp() {
+	fmt.Println(`Write a commented starter project definition file
+
+Usage:
+  go-projo init-config [flags]
+
+Flags:
+  -output string
+        Path of the config file to write (default "projo.yaml")
+  -force
+        Overwrite an existing file
+  -help
+        Show this help message
+
+Examples:
+  go-projo init-config
+  go-projo init-config -output services/billing.yaml`)
+}
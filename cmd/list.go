@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+// Example values used to render a project type for show
+const (
+	showName   = "myproject"
+	showModule = "github.com/user/myproject"
+)
+
+// typeSummary describes a project type in list and show output
+type typeSummary struct {
+	Name        generator.ProjectType        `json:"name"`
+	Aliases     []string                     `json:"aliases"`
+	Description string                       `json:"description"`
+	Variables   []generator.TemplateVariable `json:"variables"`
+}
+
+// featureSummary describes a feature in list and show output
+type featureSummary struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Types         []string `json:"types"`
+	Requires      []string `json:"requires"`
+	RequiresOneOf []string `json:"requires_one_of"`
+	Conflicts     []string `json:"conflicts"`
+}
+
+func summarizeFeature(f generator.Feature) featureSummary {
+	s := featureSummary{
+		Name:          f.Name,
+		Description:   f.Description,
+		Types:         []string{},
+		Requires:      append([]string{}, f.Requires...),
+		RequiresOneOf: append([]string{}, f.RequiresOneOf...),
+		Conflicts:     append([]string{}, f.Conflicts...),
+	}
+	for _, t := range f.Types {
+		s.Types = append(s.Types, string(t))
+	}
+	return s
+}
+
+func summarizeType(def generator.TypeDefinition) typeSummary {
+	s := typeSummary{
+		Name:        def.Name,
+		Aliases:     def.Aliases,
+		Description: def.Description,
+		Variables:   def.Variables,
+	}
+	if s.Aliases == nil {
+		s.Aliases = []string{}
+	}
+	if s.Variables == nil {
+		s.Variables = []generator.TemplateVariable{}
+	}
+	return s
+}
+
+func executeList() error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+
+	var (
+		outputFormat = fs.String("output-format", formatText, "Output format: text, json")
+		help         = fs.Bool("help", false, "Show help message")
+	)
+
+	fs.Usage = func() {
+		showListHelp()
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+
+	if *help {
+		showListHelp()
+		return nil
+	}
+
+	if err := checkOutputFormat(*outputFormat); err != nil {
+		return err
+	}
+
+	if *outputFormat == formatJSON {
+		out := struct {
+			Types    []typeSummary    `json:"types"`
+			Features []featureSummary `json:"features"`
+		}{Types: []typeSummary{}, Features: []featureSummary{}}
+		for _, def := range generator.ProjectTypes() {
+			out.Types = append(out.Types, summarizeType(def))
+		}
+		for _, f := range generator.Features() {
+			out.Features = append(out.Features, summarizeFeature(f))
+		}
+		return writeJSON(out)
+	}
+
+	fmt.Println("Project types:")
+	fmt.Println(generator.TypeHelp("  "))
+	fmt.Println("\nFeatures (add with gen -with):")
+	for _, f := range generator.Features() {
+		fmt.Printf("  %s\n", f.Name)
+		fmt.Printf("      %s\n", f.Description)
+		printFeatureRules(f, "      ")
+	}
+	fmt.Println("\nRun 'go-projo show <type>' to see what a type generates.")
+	return nil
+}
+
+// printFeatureRules prints the types, dependencies and conflicts of a
+// feature, each line starting with indent
+func printFeatureRules(f generator.Feature, indent string) {
+	if len(f.Types) > 0 {
+		types := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			types[i] = string(t)
+		}
+		fmt.Printf("%sTypes: %s\n", indent, strings.Join(types, ", "))
+	}
+	if len(f.Requires) > 0 {
+		fmt.Printf("%sAdds: %s\n", indent, strings.Join(f.Requires, ", "))
+	}
+	if len(f.RequiresOneOf) > 0 {
+		fmt.Printf("%sRequires one of: %s\n", indent, strings.Join(f.RequiresOneOf, ", "))
+	}
+	if len(f.Conflicts) > 0 {
+		fmt.Printf("%sConflicts with: %s\n", indent, strings.Join(f.Conflicts, ", "))
+	}
+}
+
+// showOutput is the document printed by show -output-format json
+type showOutput struct {
+	typeSummary
+	// With lists the selected features, including the ones they require
+	With []string `json:"with"`
+	// Data lists the standard template data; declared variables are in
+	// Variables
+	Data        []generator.TemplateField `json:"data"`
+	Directories []string                  `json:"directories"`
+	Files       []showFile                `json:"files"`
+	// Features lists the features available to the type
+	Features []featureSummary `json:"features"`
+}
+
+// showFile is a file a project type generates, rendered for the example project
+type showFile struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+func executeShow() error {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+
+	var (
+		templateDir  = fs.String("template-dir", "", "Show a template directory instead of a registered type")
+		with         listFlag
+		outputFormat = fs.String("output-format", formatText, "Output format: text, json")
+		help         = fs.Bool("help", false, "Show help message")
+	)
+
+	fs.Var(&with, "with", "Comma-separated features to include (repeatable)")
+
+	fs.Usage = func() {
+		showShowHelp()
+	}
+
+	// Accept the type before or after the flags
+	args := os.Args[2:]
+	var typeName string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		typeName, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if typeName == "" && fs.NArg() > 0 {
+		typeName = fs.Arg(0)
+	}
+
+	if *help {
+		showShowHelp()
+		return nil
+	}
+
+	if err := checkOutputFormat(*outputFormat); err != nil {
+		return err
+	}
+
+	config := generator.ProjectConfig{
+		Name:     showName,
+		Module:   showModule,
+		Features: with,
+	}
+
+	var summary typeSummary
+	switch {
+	case *templateDir != "":
+		custom, err := generator.LoadTemplateDir(*templateDir)
+		if err != nil {
+			return err
+		}
+		config.TemplateDir = *templateDir
+		summary = typeSummary{
+			Name:        generator.ProjectType(custom.Manifest.Name),
+			Aliases:     []string{},
+			Description: custom.Manifest.Description,
+			Variables:   custom.Manifest.Variables,
+		}
+		if summary.Variables == nil {
+			summary.Variables = []generator.TemplateVariable{}
+		}
+		// Required variables get a placeholder so the files can render
+		config.Vars = map[string]string{}
+		for _, v := range custom.Manifest.Variables {
+			if v.Required {
+				config.Vars[v.Name] = "<" + v.Name + ">"
+			}
+		}
+	case typeName != "":
+		def, ok := generator.LookupType(typeName)
+		if !ok {
+			_, err := generator.ParseProjectType(typeName)
+			return err
+		}
+		config.Type = def.Name
+		summary = summarizeType(def)
+		config.Vars = map[string]string{}
+		for _, v := range def.Variables {
+			if v.Required {
+				config.Vars[v.Name] = "<" + v.Name + ">"
+			}
+		}
+	default:
+		return fmt.Errorf("usage: go-projo show <type>\nRun 'go-projo list' to see the available types")
+	}
+
+	gen, err := generator.New(config)
+	if err != nil {
+		return err
+	}
+	project, err := gen.Render()
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %v", summary.Name, err)
+	}
+
+	out := showOutput{
+		typeSummary: summary,
+		With:        append([]string{}, gen.Config().Features...),
+		Data:        generator.TemplateFields(),
+		Directories: project.Directories,
+		Files:       []showFile{},
+		Features:    []featureSummary{},
+	}
+	for _, f := range generator.FeaturesFor(gen.Config().Type) {
+		out.Features = append(out.Features, summarizeFeature(f))
+	}
+	for _, f := range project.Files {
+		if f.Path != generator.ManifestFile {
+			out.Files = append(out.Files, showFile{f.Path, len(f.Content)})
+		}
+	}
+
+	if *outputFormat == formatJSON {
+		return writeJSON(out)
+	}
+	printShow(out)
+	return nil
+}
+
+// printShow prints a project type's description, tree, files, template data
+// and variables
+func printShow(out showOutput) {
+	fmt.Printf("%s - %s\n", out.Name, out.Description)
+	if len(out.Aliases) > 0 {
+		fmt.Printf("Aliases: %s\n", strings.Join(out.Aliases, ", "))
+	}
+	if len(out.With) > 0 {
+		fmt.Printf("With: %s\n", strings.Join(out.With, ", "))
+	}
+
+	files := make([]string, len(out.Files))
+	for i, f := range out.Files {
+		files[i] = f.Path
+	}
+	fmt.Printf("\nLayout (for -name %s):\n\n", showName)
+	fmt.Print(formatTree(showName, out.Directories, files))
+
+	fmt.Printf("\nFiles (%d):\n", len(out.Files))
+	for _, f := range out.Files {
+		fmt.Printf("  %s (%d bytes)\n", f.Path, f.Size)
+	}
+
+	width := 0
+	for _, d := range out.Data {
+		width = max(width, len(d.Name))
+	}
+	fmt.Println("\nTemplate data:")
+	for _, d := range out.Data {
+		fmt.Printf("  %-*s  %s\n", width, d.Name, d.Description)
+	}
+
+	fmt.Println("\nVariables (.Vars):")
+	if len(out.Variables) == 0 {
+		fmt.Println("  none declared; any -var name=value is available as .Vars.name")
+	}
+	for _, v := range out.Variables {
+		line := fmt.Sprintf("  %s", v.Name)
+		switch {
+		case v.Required:
+			line += " (required)"
+		case v.Default != "":
+			line += fmt.Sprintf(" (default %q)", v.Default)
+		}
+		if v.Description != "" {
+			line += " - " + v.Description
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println("\nFeatures:")
+	if len(out.Features) == 0 {
+		fmt.Println("  none")
+	}
+	for _, f := range out.Features {
+		fmt.Printf("  %s - %s\n", f.Name, f.Description)
+	}
+}
+
+func showListHelp() {
+	fmt.Println(`List the available project types and features
+
+Usage:
+  go-projo list [flags]
+
+Flags:
+  -output-format string
+        Output format: text, json (default "text")
+  -help
+        Show this help message`)
+}
+
+func showShowHelp() {
+	fmt.Println(`Show what a project type generates
+
+Prints the directory tree and the files of a project type, rendered for an
+example project named myproject, the data its templates receive, the
+variables it declares and the features that can be added to it.
+
+Usage:
+  go-projo show <type> [flags]
+  go-projo show -template-dir <dir> [flags]
+
+Flags:
+  -template-dir string
+        Show a template directory instead of a registered type
+  -with list
+        Comma-separated features to include, as with gen -with (repeatable)
+  -output-format string
+        Output format: text, json (default "text")
+  -help
+        Show this help message
+
+Examples:
+  go-projo show microservice
+  go-projo show lib -output-format json
+  go-projo show api -with postgres,migrate
+  go-projo show -template-dir ./our-templates`)
+}
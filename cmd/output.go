@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Output formats accepted by -output-format
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// checkOutputFormat validates the value of an -output-format flag
+func checkOutputFormat(format string) error {
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("invalid output format '%s'. Must be one of: %s, %s", format, formatText, formatJSON)
+	}
+	return nil
+}
+
+// writeJSON prints v to stdout as indented JSON
+func writeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// stdinIsTerminal reports whether stdin is attached to a terminal, so
+// prompts can be skipped when go-projo runs in CI or from a script
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	// /dev/null is a character device too, but nobody is typing into it
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+// treeNode is a directory or file in the printed project tree
+type treeNode struct {
+	name     string
+	isDir    bool
+	children map[string]*treeNode
+}
+
+func (n *treeNode) child(name string, isDir bool) *treeNode {
+	if n.children == nil {
+		n.children = make(map[string]*treeNode)
+	}
+	c, ok := n.children[name]
+	if !ok {
+		c = &treeNode{name: name}
+		n.children[name] = c
+	}
+	c.isDir = c.isDir || isDir
+	return c
+}
+
+func (n *treeNode) add(p string, isDir bool) {
+	parts := strings.Split(path.Clean(p), "/")
+	node := n
+	for i, part := range parts {
+		node = node.child(part, isDir || i < len(parts)-1)
+	}
+}
+
+func (n *treeNode) print(sb *strings.Builder, prefix string) {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		c := n.children[name]
+		branch, next := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, next = "└── ", "    "
+		}
+		label := c.name
+		if c.isDir {
+			label += "/"
+		}
+		sb.WriteString(prefix + branch + label + "\n")
+		c.print(sb, prefix+next)
+	}
+}
+
+// formatPlanTree renders the plan as a directory tree rooted at the project name
+func formatPlanTree(plan *generator.Plan) string {
+	files := make([]string, len(plan.Files))
+	for i, file := range plan.Files {
+		files[i] = file.Path
+	}
+	return formatTree(filepath.Base(plan.BasePath), plan.Directories, files)
+}
+
+// formatTree renders directories and files as a tree rooted at name
+func formatTree(name string, dirs, files []string) string {
+	root := &treeNode{name: name, isDir: true}
+	for _, dir := range dirs {
+		root.add(dir, true)
+	}
+	for _, file := range files {
+		root.add(file, false)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(root.name + "/\n")
+	root.print(&sb, "")
+	return sb.String()
+}
+
+// printPlan prints the tree, the file list and optionally every file diff
+func printPlan(plan *generator.Plan, showDiff bool) {
+	fmt.Printf("Plan for %s:\n\n", plan.BasePath)
+	fmt.Print(formatPlanTree(plan))
+
+	fmt.Println("\nFiles:")
+	for _, file := range plan.Files {
+		marker := "+"
+		switch file.Status {
+		case generator.FileChanged:
+			marker = "~"
+		case generator.FileUnchanged:
+			marker = "="
+		}
+		fmt.Printf("  %s %s (%d bytes, %s)\n", marker, file.Path, file.Size, file.Status)
+	}
+
+	if !showDiff {
+		return
+	}
+
+	for _, file := range plan.Files {
+		if file.Diff == "" {
+			continue
+		}
+		fmt.Println()
+		fmt.Print(file.Diff)
+	}
+}
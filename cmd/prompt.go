@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// stdin is shared by every prompt so buffered input is never lost between
+// questions
+var stdin = bufio.NewReader(os.Stdin)
+
+// readLine prints question and returns the trimmed answer; at end of input it
+// returns whatever was typed before it
+func readLine(question string) (string, error) {
+	fmt.Print(question)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// confirm asks a yes/no question; anything but y or yes means no
+func confirm(question string) bool {
+	answer, err := readLine(question + " (y/n): ")
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes"
+}
@@ -3,9 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-)
 
-var version = "1.0.0"
+	"github.com/yogabagas/gen-projo/generator"
+)
 
 // Execute runs the CLI application
 func Execute() error {
@@ -17,8 +17,22 @@ func Execute() error {
 	switch os.Args[1] {
 	case "gen", "generate":
 		return executeGenerate()
+	case "init-config":
+		return executeInitConfig()
+	case "config":
+		return executeConfig()
+	case "upgrade":
+		return executeUpgrade()
+	case "diff":
+		return executeDiff()
+	case "doctor":
+		return executeDoctor()
+	case "list":
+		return executeList()
+	case "show":
+		return executeShow()
 	case "version", "-v", "--version":
-		fmt.Printf("go-projo version %s\n", version)
+		fmt.Printf("go-projo version %s\n", generator.Version)
 		return nil
 	case "help", "-h", "--help":
 		showHelp()
@@ -36,12 +50,23 @@ Usage:
 
 Commands:
   gen, generate    Generate a new Go project
+  init-config      Write a starter project definition file (projo.yaml)
+  config           Manage user and organization defaults
+  upgrade          Re-apply the current templates to a generated project
+  diff             Show how a generated project differs from its templates
+  doctor           Check that a project conforms to its project type
+  list             List the available project types and features
+  show             Show what a project type generates
   version          Show version information
   help             Show this help message
 
 Examples:
   go-projo gen -name myapi -module github.com/user/myapi -type api
   go-projo gen -name mytool -module github.com/user/mytool -type cli
+  go-projo gen -config projo.yaml
+  go-projo config set module_prefix github.com/user
+  go-projo upgrade -dir ./myapi -dry-run
+  go-projo show microservice
   go-projo version
 
 Run 'go-projo gen -help' for more information about the generate command.`)
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+func executeUpgrade() error {
+	fs := flag.NewFlagSet("upgrade", flag.ExitOnError)
+
+	var (
+		dir          = fs.String("dir", ".", "Directory of the project to upgrade")
+		templateDir  = fs.String("template-dir", "", "Template directory to use instead of the recorded one")
+		dryRun       = fs.Bool("dry-run", false, "Report what would change without writing anything")
+		outputFormat = fs.String("output-format", formatText, "Output format: text, json")
+		help         = fs.Bool("help", false, "Show help message")
+	)
+
+	fs.Usage = func() {
+		showUpgradeHelp()
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+
+	if *help {
+		showUpgradeHelp()
+		return nil
+	}
+
+	if err := checkOutputFormat(*outputFormat); err != nil {
+		return err
+	}
+
+	gen, manifest, err := generator.OpenProject(*dir, *templateDir)
+	if err != nil {
+		return err
+	}
+
+	result, err := gen.Upgrade(manifest, *dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade project: %v", err)
+	}
+
+	if *outputFormat == formatJSON {
+		if err := writeJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printUpgradeResult(result, *dryRun)
+	}
+
+	if len(result.Conflicted) > 0 && !*dryRun {
+		return fmt.Errorf("%d file(s) have conflicts; resolve the markers by hand", len(result.Conflicted))
+	}
+	return nil
+}
+
+// printUpgradeResult prints what upgrade did, or would do, with each file
+func printUpgradeResult(result *generator.UpgradeResult, dryRun bool) {
+	fmt.Printf("Project: %s\n", result.BasePath)
+	fmt.Printf("Generated by go-projo %s, upgrading with %s\n", result.FromVersion, result.ToVersion)
+	if !result.TemplatesChanged {
+		fmt.Println("Templates are unchanged since the project was generated")
+	}
+
+	sections := []struct {
+		title string
+		paths []string
+	}{
+		{"Added", result.Added},
+		{"Updated", result.Updated},
+		{"Merged", result.Merged},
+		{"Merged with conflicts (resolve the markers by hand)", result.Conflicted},
+		{"Kept (local changes, template unchanged)", result.Kept},
+		{"Deleted locally (not recreated)", result.Deleted},
+		{"No longer generated (delete them if unused)", result.Removed},
+	}
+
+	changed := false
+	for _, section := range sections {
+		if len(section.paths) == 0 {
+			continue
+		}
+		changed = true
+		fmt.Printf("\n%s (%d):\n", section.title, len(section.paths))
+		for _, p := range section.paths {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+
+	switch {
+	case !changed:
+		fmt.Println("\nProject is up to date")
+	case dryRun:
+		fmt.Println("\nDry run: no files were written")
+	}
+}
+
+func showUpgradeHelp() {
+	fmt.Println(`Re-apply the current templates to a generated project
+
+Reads the project's .projo.json manifest, renders the templates with the
+recorded settings and merges the changes into the files on disk:
+
+  - files you have not edited are replaced
+  - files you edited are merged three-way against the content they were
+    generated with; overlapping changes get conflict markers
+  - files you deleted stay deleted
+  - new template files are added
+
+The manifest is refreshed afterwards. Exits non-zero when conflicts remain.
+
+Usage:
+  go-projo upgrade [flags]
+
+Flags:
+  -dir string
+        Directory of the project to upgrade (default ".")
+  -template-dir string
+        Template directory to use instead of the one recorded in the
+        manifest, for custom templates that have moved
+  -dry-run
+        Report what would change without writing anything
+  -output-format string
+        Output format: text, json (default "text")
+  -help
+        Show this help message
+
+Examples:
+  # Preview an upgrade of the project in the current directory
+  go-projo upgrade -dry-run
+
+  # Upgrade a project elsewhere
+  go-projo upgrade -dir ~/src/myapi`)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yogabagas/gen-projo/generator"
+)
+
+// runWizard walks through every project setting on stdin, offering the
+// values already in settings as defaults
+func runWizard(settings *generator.ConfigFile) error {
+	fmt.Println("=== Go Project Generator: interactive setup ===")
+	fmt.Println("Press Enter to accept the value in [brackets].")
+	fmt.Println()
+
+	if settings.Origins == nil {
+		settings.Origins = make(map[string]string)
+	}
+	for _, key := range []string{"name", "module", "type", "description", "author", "go_version", "output"} {
+		settings.Origins[key] = "interactive setup"
+	}
+
+	var err error
+
+	settings.Name, err = ask("Project name", settings.Name, generator.ValidateName)
+	if err != nil {
+		return err
+	}
+
+	// Suggest values inferred from git, go.work and the installed toolchain
+	inferred := generator.ProjectConfig{
+		Name:       settings.Name,
+		Module:     settings.Module,
+		Author:     settings.Author,
+		GoVersion:  settings.GoVersion,
+		OutputPath: settings.Output,
+	}
+	generator.InferDefaults(&inferred, settings.ModulePrefix)
+
+	settings.Module, err = ask("Module path", inferred.Module, generator.ValidateModulePath)
+	if err != nil {
+		return err
+	}
+
+	if settings.TemplateDir == "" {
+		if settings.Type, err = askProjectType(settings.Type); err != nil {
+			return err
+		}
+		if err := askFeatures(settings); err != nil {
+			return err
+		}
+	}
+
+	if settings.Description, err = ask("Description", settings.Description, nil); err != nil {
+		return err
+	}
+
+	if settings.Author, err = ask("Author", inferred.Author, nil); err != nil {
+		return err
+	}
+
+	settings.GoVersion, err = ask("Go version", inferred.GoVersion, generator.ValidateGoVersion)
+	if err != nil {
+		return err
+	}
+
+	if settings.Output, err = ask("Output directory", settings.Output, nil); err != nil {
+		return err
+	}
+
+	// Optional settings
+	fmt.Println()
+	if !confirm("Configure optional settings?") {
+		fmt.Println()
+		return nil
+	}
+
+	exclude, err := ask("Files or directories to exclude (comma-separated)", strings.Join(settings.Exclude, ","), nil)
+	if err != nil {
+		return err
+	}
+	settings.Exclude = nil
+	(*listFlag)(&settings.Exclude).Set(exclude)
+
+	settings.OnConflict, err = ask("If files already exist (fail, skip, overwrite, prompt, merge)", settings.OnConflict, func(v string) error {
+		_, err := generator.ParseConflictPolicy(v)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// askFeatures offers the features available to the chosen project type
+func askFeatures(settings *generator.ConfigFile) error {
+	pType, err := generator.ParseProjectType(settings.Type)
+	if err != nil {
+		return err
+	}
+	choices := generator.FeaturesFor(pType)
+	if len(choices) == 0 {
+		return nil
+	}
+
+	fmt.Println("Features:")
+	for _, choice := range choices {
+		fmt.Printf("  %-13s %s\n", choice.Name, choice.Description)
+	}
+
+	answer, err := ask("Features to add (comma-separated, empty for none)", strings.Join(settings.Features, ","), func(v string) error {
+		var names []string
+		(*listFlag)(&names).Set(v)
+		_, err := generator.ResolveFeatures(pType, names)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	settings.Features = nil
+	(*listFlag)(&settings.Features).Set(answer)
+	settings.Origins["features"] = "interactive setup"
+	return nil
+}
+
+// ask prompts for a value until validate accepts it; an empty answer keeps def
+func ask(label, def string, validate func(string) error) (string, error) {
+	for {
+		question := label + ": "
+		if def != "" {
+			question = fmt.Sprintf("%s [%s]: ", label, def)
+		}
+
+		answer, err := readLine(question)
+		if err != nil {
+			return "", fmt.Errorf("interactive setup aborted: %v", err)
+		}
+		if answer == "" {
+			answer = def
+		}
+
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// askProjectType shows the available types and accepts a number or a name
+func askProjectType(def string) (string, error) {
+	choices := generator.ProjectTypes()
+
+	fmt.Println("Project type:")
+	for i, choice := range choices {
+		fmt.Printf("  %d) %-13s %s\n", i+1, choice.Name, choice.Description)
+	}
+
+	answer, err := ask("Choose a type", def, func(v string) error {
+		if n, err := strconv.Atoi(v); err == nil {
+			if n < 1 || n > len(choices) {
+				return fmt.Errorf("choose a number between 1 and %d", len(choices))
+			}
+			return nil
+		}
+		_, err := generator.ParseProjectType(v)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if n, err := strconv.Atoi(answer); err == nil {
+		return string(choices[n-1].Name), nil
+	}
+	return answer, nil
+}
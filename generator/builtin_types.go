@@ -0,0 +1,169 @@
+package generator
+
+import "path"
+
+// The built-in project types, in the order they are listed
+func init() {
+	MustRegisterType(TypeDefinition{
+		Name:        ProjectTypeAPI,
+		Description: "REST API server with HTTP handlers",
+		Build:       buildAPIStructure,
+	})
+	MustRegisterType(TypeDefinition{
+		Name:        ProjectTypeCLI,
+		Description: "Command-line tool",
+		Build:       buildCLIStructure,
+	})
+	MustRegisterType(TypeDefinition{
+		Name:        ProjectTypeMicro,
+		Aliases:     []string{"micro"},
+		Description: "Microservice with HTTP/gRPC and Docker/K8s configs",
+		Build:       buildMicroserviceStructure,
+	})
+	MustRegisterType(TypeDefinition{
+		Name:        ProjectTypeLibrary,
+		Aliases:     []string{"lib"},
+		Description: "Reusable Go library package",
+		Build:       buildLibraryStructure,
+	})
+}
+
+// buildAPIStructure creates structure for REST API projects
+func buildAPIStructure(config ProjectConfig) ProjectStructure {
+	return ProjectStructure{
+		Directories: []string{
+			"cmd/api",
+			"internal/handler",
+			"internal/service",
+			"internal/repository",
+			"internal/model",
+			"internal/middleware",
+			"internal/config",
+			"pkg/response",
+			"pkg/validator",
+			"migrations",
+			"docs",
+			"scripts",
+		},
+		Files: builtinFiles(ProjectTypeAPI,
+			"go.mod",
+			"README.md",
+			".gitignore",
+			"internal/config/config.go",
+			"internal/handler/handler.go",
+			"internal/service/service.go",
+			"internal/repository/repository.go",
+			"internal/model/model.go",
+			"pkg/response/response.go",
+		),
+		Partials: builtinPartials(ProjectTypeAPI),
+	}
+}
+
+// buildCLIStructure creates structure for CLI tool projects
+func buildCLIStructure(config ProjectConfig) ProjectStructure {
+	return ProjectStructure{
+		Directories: []string{
+			"cmd",
+			"internal/command",
+			"internal/config",
+			"pkg/utils",
+			"docs",
+		},
+		Files: builtinFiles(ProjectTypeCLI,
+			"go.mod",
+			"README.md",
+			".gitignore",
+			"internal/config/config.go",
+		),
+		Partials: builtinPartials(ProjectTypeCLI),
+	}
+}
+
+// buildMicroserviceStructure creates structure for microservice projects
+func buildMicroserviceStructure(config ProjectConfig) ProjectStructure {
+	return ProjectStructure{
+		Directories: []string{
+			"cmd/server",
+			"internal/handler",
+			"internal/service",
+			"internal/repository",
+			"internal/model",
+			"internal/middleware",
+			"internal/config",
+			"pkg/grpc",
+			"pkg/http",
+			"pkg/response",
+			"proto",
+			"migrations",
+			"deployments/docker",
+			"deployments/k8s",
+			"scripts",
+		},
+		Files: builtinFiles(ProjectTypeMicro,
+			"go.mod",
+			"README.md",
+			".gitignore",
+			"internal/config/config.go",
+			"internal/handler/handler.go",
+			"internal/service/service.go",
+			"internal/repository/repository.go",
+			"internal/model/model.go",
+			"pkg/response/response.go",
+		),
+		Partials: builtinPartials(ProjectTypeMicro),
+	}
+}
+
+// buildLibraryStructure creates structure for library projects
+func buildLibraryStructure(config ProjectConfig) ProjectStructure {
+	return ProjectStructure{
+		Directories: []string{
+			"internal",
+			"examples",
+			"docs",
+		},
+		Files: builtinFiles(ProjectTypeLibrary,
+			"go.mod",
+			"README.md",
+			".gitignore",
+		),
+		Partials: builtinPartials(ProjectTypeLibrary),
+	}
+}
+
+// builtinFiles returns the embedded templates of a built-in project type
+// together with the named templates from the shared directory
+func builtinFiles(projectType ProjectType, shared ...string) map[string]string {
+	files, err := builtinTemplates.Files(string(projectType))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, name := range shared {
+		content, err := builtinTemplates.File(path.Join(sharedTemplateDir, name))
+		if err != nil {
+			panic(err)
+		}
+		files[name] = content
+	}
+
+	return files
+}
+
+// builtinPartials returns the shared partials plus those of a built-in type
+func builtinPartials(projectType ProjectType) map[string]string {
+	partials := make(map[string]string)
+
+	for _, dir := range []string{sharedTemplateDir, string(projectType)} {
+		found, err := builtinTemplates.Partials(dir)
+		if err != nil {
+			panic(err)
+		}
+		for name, content := range found {
+			partials[path.Join(dir, name)] = content
+		}
+	}
+
+	return partials
+}
@@ -0,0 +1,349 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the conventional name of a project definition file
+const DefaultConfigFile = "projo.yaml"
+
+// ConfigFile is the on-disk form of a project definition. It mirrors the
+// flags of the gen command so a scaffold can be version-controlled and
+// reproduced exactly.
+type ConfigFile struct {
+	Name        string `yaml:"name,omitempty" json:"name,omitempty"`
+	Module      string `yaml:"module,omitempty" json:"module,omitempty"`
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Author      string `yaml:"author,omitempty" json:"author,omitempty"`
+	License     string `yaml:"license,omitempty" json:"license,omitempty"`
+	GoVersion   string `yaml:"go_version,omitempty" json:"go_version,omitempty"`
+	// ModulePrefix derives Module as ModulePrefix/Name when Module is empty
+	ModulePrefix string            `yaml:"module_prefix,omitempty" json:"module_prefix,omitempty"`
+	Output       string            `yaml:"output,omitempty" json:"output,omitempty"`
+	OnConflict   string            `yaml:"on_conflict,omitempty" json:"on_conflict,omitempty"`
+	GoCheck      string            `yaml:"go_check,omitempty" json:"go_check,omitempty"`
+	TemplateDir  string            `yaml:"template_dir,omitempty" json:"template_dir,omitempty"`
+	OverrideDir  string            `yaml:"override_dir,omitempty" json:"override_dir,omitempty"`
+	Exclude      []string          `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+	Features     []string          `yaml:"features,omitempty" json:"features,omitempty"`
+	Hooks        []string          `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	Vars         map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`
+
+	// Source names where the values came from, e.g. "flag" or a file path
+	Source string `yaml:"-" json:"-"`
+	// Origins records, per key, the Source of the layer that set it last
+	Origins map[string]string `yaml:"-" json:"-"`
+}
+
+// LoadConfigFile reads a YAML or JSON (by .json extension) config file.
+// Unknown keys are rejected, and relative paths are resolved against the
+// directory containing the file.
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg := ConfigFile{Source: path}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for _, f := range cfg.fields() {
+		if f.path && *f.ptr != "" {
+			*f.ptr = resolvePath(dir, *f.ptr)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// resolvePath expands a leading ~ and makes p relative to dir
+func resolvePath(dir, p string) string {
+	p = ExpandHome(p)
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+// ExpandHome replaces a leading ~ or ~/ in p with the home directory, for
+// paths that did not go through a shell. Other paths are returned as is.
+func ExpandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") && !strings.HasPrefix(p, "~"+string(filepath.Separator)) {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[1:])
+}
+
+// configField binds a scalar config key to its field
+type configField struct {
+	key  string
+	ptr  *string
+	path bool
+}
+
+// fields lists the scalar keys of c in file order
+func (c *ConfigFile) fields() []configField {
+	return []configField{
+		{"name", &c.Name, false},
+		{"module", &c.Module, false},
+		{"type", &c.Type, false},
+		{"description", &c.Description, false},
+		{"author", &c.Author, false},
+		{"license", &c.License, false},
+		{"go_version", &c.GoVersion, false},
+		{"module_prefix", &c.ModulePrefix, false},
+		{"output", &c.Output, true},
+		{"on_conflict", &c.OnConflict, false},
+		{"go_check", &c.GoCheck, false},
+		{"template_dir", &c.TemplateDir, true},
+		{"override_dir", &c.OverrideDir, true},
+	}
+}
+
+// ConfigKeys lists every key accepted by Get and Set. Template variables
+// are addressed as vars.<name>.
+func ConfigKeys() []string {
+	var c ConfigFile
+	keys := []string{}
+	for _, f := range c.fields() {
+		keys = append(keys, f.key)
+	}
+	return append(keys, "exclude", "features", "hooks", "vars.<name>")
+}
+
+// Get returns the value of key; list keys are returned comma-separated
+func (c *ConfigFile) Get(key string) (string, error) {
+	for _, f := range c.fields() {
+		if f.key == key {
+			return *f.ptr, nil
+		}
+	}
+
+	switch {
+	case key == "exclude":
+		return strings.Join(c.Exclude, ","), nil
+	case key == "features":
+		return strings.Join(c.Features, ","), nil
+	case key == "hooks":
+		return strings.Join(c.Hooks, ","), nil
+	case strings.HasPrefix(key, "vars."):
+		return c.Vars[strings.TrimPrefix(key, "vars.")], nil
+	}
+
+	return "", fmt.Errorf("unknown config key '%s'. Valid keys: %s", key, strings.Join(ConfigKeys(), ", "))
+}
+
+// Set assigns value to key; an empty value clears it. Exclude, features
+// and hooks take a comma-separated list.
+func (c *ConfigFile) Set(key, value string) error {
+	for _, f := range c.fields() {
+		if f.key == key {
+			*f.ptr = value
+			return nil
+		}
+	}
+
+	switch {
+	case key == "exclude":
+		c.Exclude = splitList(value)
+		return nil
+	case key == "features":
+		c.Features = splitList(value)
+		return nil
+	case key == "hooks":
+		c.Hooks = splitList(value)
+		return nil
+	case strings.HasPrefix(key, "vars.") && key != "vars.":
+		name := strings.TrimPrefix(key, "vars.")
+		if value == "" {
+			delete(c.Vars, name)
+			return nil
+		}
+		if c.Vars == nil {
+			c.Vars = make(map[string]string)
+		}
+		c.Vars[name] = value
+		return nil
+	}
+
+	return fmt.Errorf("unknown config key '%s'. Valid keys: %s", key, strings.Join(ConfigKeys(), ", "))
+}
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IsPathKey reports whether key holds a filesystem path
+func IsPathKey(key string) bool {
+	var c ConfigFile
+	for _, f := range c.fields() {
+		if f.key == key {
+			return f.path
+		}
+	}
+	return false
+}
+
+// Merge overlays every non-empty field of other onto c, recording
+// other.Source as the origin of each value it sets. Vars are merged key by
+// key; Exclude, Features and Hooks are replaced as a whole.
+func (c *ConfigFile) Merge(other *ConfigFile) {
+	if other == nil {
+		return
+	}
+
+	if c.Origins == nil {
+		c.Origins = make(map[string]string)
+	}
+
+	src := other.fields()
+	for i, f := range c.fields() {
+		if *src[i].ptr != "" {
+			*f.ptr = *src[i].ptr
+			c.Origins[f.key] = other.Source
+		}
+	}
+
+	if len(other.Exclude) > 0 {
+		c.Exclude = append([]string(nil), other.Exclude...)
+		c.Origins["exclude"] = other.Source
+	}
+
+	if len(other.Features) > 0 {
+		c.Features = append([]string(nil), other.Features...)
+		c.Origins["features"] = other.Source
+	}
+
+	if len(other.Hooks) > 0 {
+		c.Hooks = append([]string(nil), other.Hooks...)
+		c.Origins["hooks"] = other.Source
+	}
+
+	if len(other.Vars) > 0 {
+		if c.Vars == nil {
+			c.Vars = make(map[string]string, len(other.Vars))
+		}
+		for k, v := range other.Vars {
+			c.Vars[k] = v
+			c.Origins["vars."+k] = other.Source
+		}
+	}
+}
+
+// SaveConfigFile writes c to path, as JSON for a .json extension and YAML
+// otherwise, creating parent directories
+func SaveConfigFile(path string, c *ConfigFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+	} else {
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// Environment variables locating the defaults files
+const (
+	// UserConfigEnv overrides the path of the user defaults file
+	UserConfigEnv = "GO_PROJO_CONFIG"
+	// OrgConfigEnv points at an organization-wide defaults file
+	OrgConfigEnv = "GO_PROJO_ORG_CONFIG"
+)
+
+// UserConfigPath returns the path of the user defaults file:
+// $GO_PROJO_CONFIG, or go-projo/config.yaml under $XDG_CONFIG_HOME
+// (~/.config when unset)
+func UserConfigPath() (string, error) {
+	if p := os.Getenv(UserConfigEnv); p != "" {
+		return p, nil
+	}
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "go-projo", "config.yaml"), nil
+}
+
+// LoadDefaults reads the organization and user defaults files, in that
+// order of increasing precedence. Files that do not exist are skipped.
+func LoadDefaults() ([]*ConfigFile, error) {
+	var paths []string
+	if p := os.Getenv(OrgConfigEnv); p != "" {
+		paths = append(paths, p)
+	}
+
+	userPath, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, userPath)
+
+	var layers []*ConfigFile
+	for _, p := range paths {
+		if _, err := os.Stat(p); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		layer, err := LoadConfigFile(p)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
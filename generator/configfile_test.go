@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigFileSet(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{key: "name", value: "myapi", want: "myapi"},
+		{key: "go_version", value: "1.24", want: "1.24"},
+		{key: "exclude", value: "docs, ,Makefile", want: "docs,Makefile"},
+		{key: "features", value: "postgres,migrate", want: "postgres,migrate"},
+		{key: "hooks", value: "gofmt", want: "gofmt"},
+		{key: "vars.Port", value: "9000", want: "9000"},
+		{key: "name", value: "", want: ""},
+		{key: "colour", value: "blue", wantErr: true},
+		{key: "vars.", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		c := &ConfigFile{Name: "before"}
+		err := c.Set(tt.key, tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Set(%q, %q) succeeded, want an error", tt.key, tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q, %q) error = %v", tt.key, tt.value, err)
+			continue
+		}
+		if got, _ := c.Get(tt.key); got != tt.want {
+			t.Errorf("Get(%q) after Set(%q) = %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+	}
+
+	c := &ConfigFile{Vars: map[string]string{"Port": "9000"}}
+	if err := c.Set("vars.Port", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Vars["Port"]; ok {
+		t.Errorf("Set(vars.Port, \"\") kept the variable")
+	}
+}
+
+func TestConfigFileMerge(t *testing.T) {
+	c := &ConfigFile{Type: "api", Output: ".", Source: OriginDefault}
+	layers := []*ConfigFile{
+		{Source: "org", License: "Apache-2.0", Exclude: []string{"docs"}, Vars: map[string]string{"A": "org", "B": "org"}},
+		{Source: "user", License: "MIT", Author: "User", Vars: map[string]string{"B": "user"}},
+		{Source: "flag", Type: "cli", Exclude: []string{"Makefile"}},
+		nil,
+	}
+	for _, layer := range layers {
+		c.Merge(layer)
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		origin string
+	}{
+		{"type", "cli", "flag"},
+		{"output", ".", ""},
+		{"license", "MIT", "user"},
+		{"author", "User", "user"},
+		{"exclude", "Makefile", "flag"},
+		{"vars.A", "org", "org"},
+		{"vars.B", "user", "user"},
+	}
+	for _, tt := range tests {
+		got, err := c.Get(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.value {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.value)
+		}
+		if c.Origins[tt.key] != tt.origin {
+			t.Errorf("origin of %s = %q, want %q", tt.key, c.Origins[tt.key], tt.origin)
+		}
+	}
+}
+
+func TestSaveConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	saved := &ConfigFile{
+		Name:     "myapi",
+		Module:   "github.com/user/myapi",
+		Type:     "microservice",
+		Exclude:  []string{"docs/API.md"},
+		Features: []string{"postgres"},
+		Hooks:    []string{"gofmt", "tidy"},
+		Vars:     map[string]string{"Port": "9000"},
+		// Relative paths are resolved against the file's directory on load
+		Output: "out",
+	}
+
+	tests := []struct {
+		file string
+		// prefix is how the saved file starts, to tell the formats apart
+		prefix string
+	}{
+		{DefaultConfigFile, "name: myapi\n"},
+		{"projo.yml", "name: myapi\n"},
+		{"projo.json", "{\n  \"name\": \"myapi\",\n"},
+		{"PROJO.JSON", "{\n"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, "nested", tt.file)
+		if err := SaveConfigFile(path, saved); err != nil {
+			t.Fatalf("SaveConfigFile(%s) error = %v", tt.file, err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(data), tt.prefix) {
+			t.Errorf("SaveConfigFile(%s) wrote %q, want it to start with %q", tt.file, data, tt.prefix)
+		}
+
+		loaded, err := LoadConfigFile(path)
+		if err != nil {
+			t.Fatalf("LoadConfigFile(%s) error = %v", tt.file, err)
+		}
+		want := *saved
+		want.Output = filepath.Join(dir, "nested", "out")
+		want.Source = path
+		if !reflect.DeepEqual(*loaded, want) {
+			t.Errorf("LoadConfigFile(%s) = %+v, want %+v", tt.file, *loaded, want)
+		}
+	}
+}
+
+func TestLoadConfigFileUnknownKey(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"projo.yaml": "name: x\nnmae: y\n",
+		"projo.json": "{\"name\": \"x\", \"nmae\": \"y\"}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfigFile(path); err == nil || !strings.Contains(err.Error(), "nmae") {
+			t.Errorf("LoadConfigFile(%s) error = %v, want one naming the unknown key", name, err)
+		}
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", home},
+		{"~/projects", filepath.Join(home, "projects")},
+		{"~/a/b", filepath.Join(home, "a", "b")},
+		{"~user/projects", "~user/projects"},
+		{"projects/~", "projects/~"},
+		{"/srv/projects", "/srv/projects"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ExpandHome(tt.path); got != tt.want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	// Path keys in config files are expanded when loading
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(path, []byte("output: ~/projects\ntemplate_dir: templates\nname: ~/x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, "projects"); c.Output != want {
+		t.Errorf("output = %q, want %q", c.Output, want)
+	}
+	if want := filepath.Join(filepath.Dir(path), "templates"); c.TemplateDir != want {
+		t.Errorf("template_dir = %q, want %q", c.TemplateDir, want)
+	}
+	if c.Name != "~/x" {
+		t.Errorf("name = %q, want it left alone", c.Name)
+	}
+}
@@ -0,0 +1,304 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ConflictPolicy controls what Generate does with files that already exist
+type ConflictPolicy string
+
+const (
+	ConflictFail      ConflictPolicy = "fail"
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictPrompt    ConflictPolicy = "prompt"
+	ConflictMerge     ConflictPolicy = "merge"
+)
+
+// ConflictPolicies lists every supported policy in display order
+var ConflictPolicies = []ConflictPolicy{
+	ConflictFail,
+	ConflictSkip,
+	ConflictOverwrite,
+	ConflictPrompt,
+	ConflictMerge,
+}
+
+// ParseConflictPolicy converts a flag value into a ConflictPolicy
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	for _, p := range ConflictPolicies {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+
+	names := make([]string, len(ConflictPolicies))
+	for i, p := range ConflictPolicies {
+		names[i] = string(p)
+	}
+	return "", fmt.Errorf("invalid conflict policy '%s'. Must be one of: %s", s, strings.Join(names, ", "))
+}
+
+// ConflictPrompter asks whether an existing file may be overwritten
+type ConflictPrompter func(path string) (bool, error)
+
+// ConflictError is returned when existing files block generation under the
+// fail policy
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%d file(s) already exist: %s (use -on-conflict to choose how to handle them)",
+		len(e.Paths), strings.Join(e.Paths, ", "))
+}
+
+// Result summarizes what Generate did with each file
+type Result struct {
+	// BasePath is the directory the project was written to; it is empty
+	// for sinks that do not write to a directory, such as archives
+	BasePath    string   `json:"base_path,omitempty"`
+	Created     []string `json:"created"`
+	Overwritten []string `json:"overwritten"`
+	Merged      []string `json:"merged"`
+	Conflicted  []string `json:"conflicted"`
+	Skipped     []string `json:"skipped"`
+	Unchanged   []string `json:"unchanged"`
+	// Warnings lists invalid Go files written under GoCheckWarn, as
+	// path:line:column: message
+	Warnings []string `json:"warnings"`
+}
+
+// resolveConflicts compares rendered files with what the sink already
+// holds, applies the conflict policy, and returns the files that should be
+// written
+func (g *Generator) resolveConflicts(sink Sink, files []renderedFile) ([]renderedFile, *Result, error) {
+	result := &Result{
+		BasePath:    sinkDir(sink),
+		Created:     []string{},
+		Overwritten: []string{},
+		Merged:      []string{},
+		Conflicted:  []string{},
+		Warnings:    []string{},
+		Skipped:     []string{},
+		Unchanged:   []string{},
+	}
+	var (
+		write     []renderedFile
+		conflicts []string
+	)
+
+	for _, file := range files {
+		existing, err := sink.ReadFile(file.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			write = append(write, file)
+			result.Created = append(result.Created, file.path)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read existing file %s: %w", file.path, err)
+		}
+
+		if string(existing) == file.content {
+			result.Unchanged = append(result.Unchanged, file.path)
+			continue
+		}
+
+		switch g.config.OnConflict {
+		case ConflictSkip:
+			result.Skipped = append(result.Skipped, file.path)
+		case ConflictOverwrite:
+			write = append(write, file)
+			result.Overwritten = append(result.Overwritten, file.path)
+		case ConflictPrompt:
+			if g.prompter == nil {
+				return nil, nil, fmt.Errorf("conflict policy '%s' requires an interactive prompt", ConflictPrompt)
+			}
+			ok, err := g.prompter(file.path)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ok {
+				write = append(write, file)
+				result.Overwritten = append(result.Overwritten, file.path)
+			} else {
+				result.Skipped = append(result.Skipped, file.path)
+			}
+		case ConflictMerge:
+			merged, clean := mergeTwoWay(string(existing), file.content)
+			write = append(write, renderedFile{path: file.path, content: merged})
+			if clean {
+				result.Merged = append(result.Merged, file.path)
+			} else {
+				result.Conflicted = append(result.Conflicted, file.path)
+			}
+		default:
+			conflicts = append(conflicts, file.path)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, nil, &ConflictError{Paths: conflicts}
+	}
+
+	return write, result, nil
+}
+
+// Conflict markers written around hunks that could not be merged
+const (
+	markerExisting = "<<<<<<< existing\n"
+	markerSplit    = "=======\n"
+	markerNew      = ">>>>>>> go-projo\n"
+)
+
+// mergeTwoWay combines an existing file with freshly generated content.
+// Lines only present in one side are kept; hunks where both sides changed
+// the same lines are wrapped in conflict markers. It reports whether the
+// merge was free of conflicts.
+func mergeTwoWay(existing, generated string) (string, bool) {
+	ops := diffLines(splitLines(existing), splitLines(generated))
+
+	var sb strings.Builder
+	clean := true
+	for i := 0; i < len(ops); {
+		if ops[i].kind == diffEqual {
+			sb.WriteString(ops[i].line)
+			i++
+			continue
+		}
+
+		var ours, theirs []string
+		for ; i < len(ops) && ops[i].kind != diffEqual; i++ {
+			if ops[i].kind == diffDelete {
+				ours = append(ours, ops[i].line)
+			} else {
+				theirs = append(theirs, ops[i].line)
+			}
+		}
+
+		switch {
+		case len(theirs) == 0:
+			writeLines(&sb, ours)
+		case len(ours) == 0:
+			writeLines(&sb, theirs)
+		default:
+			clean = false
+			writeConflict(&sb, ours, theirs)
+		}
+	}
+
+	return sb.String(), clean
+}
+
+// mergeThreeWay applies the changes between base and generated to
+// existing, the way diff3 does. Regions changed on only one side take that
+// side; regions changed differently on both sides are wrapped in conflict
+// markers. It reports whether the merge was free of conflicts.
+func mergeThreeWay(base, existing, generated string) (string, bool) {
+	baseLines := splitLines(base)
+	ours := splitLines(existing)
+	theirs := splitLines(generated)
+	toOurs := lineMatches(baseLines, ours)
+	toTheirs := lineMatches(baseLines, theirs)
+
+	var sb strings.Builder
+	clean := true
+	i, a, b := 0, 0, 0
+	for i < len(baseLines) || a < len(ours) || b < len(theirs) {
+		// A base line kept at the current position on both sides is stable
+		if i < len(baseLines) && toOurs[i] == a && toTheirs[i] == b {
+			sb.WriteString(baseLines[i])
+			i, a, b = i+1, a+1, b+1
+			continue
+		}
+
+		// Otherwise the unstable region runs up to the next base line that
+		// both sides kept, or to the end of all three texts
+		j, endA, endB := len(baseLines), len(ours), len(theirs)
+		for k := i; k < len(baseLines); k++ {
+			if toOurs[k] >= 0 && toTheirs[k] >= 0 {
+				j, endA, endB = k, toOurs[k], toTheirs[k]
+				break
+			}
+		}
+
+		baseChunk, oursChunk, theirsChunk := baseLines[i:j], ours[a:endA], theirs[b:endB]
+		switch {
+		case equalLines(oursChunk, baseChunk):
+			writeLines(&sb, theirsChunk)
+		case equalLines(theirsChunk, baseChunk), equalLines(oursChunk, theirsChunk):
+			writeLines(&sb, oursChunk)
+		default:
+			clean = false
+			writeConflict(&sb, oursChunk, theirsChunk)
+		}
+		i, a, b = j, endA, endB
+	}
+
+	return sb.String(), clean
+}
+
+// lineMatches maps every line of a to the index of the same line in b
+// along their longest common subsequence, or -1 when it was removed
+func lineMatches(a, b []string) []int {
+	matches := make([]int, len(a))
+	i, j := 0, 0
+	for _, op := range diffLines(a, b) {
+		switch op.kind {
+		case diffEqual:
+			matches[i] = j
+			i++
+			j++
+		case diffDelete:
+			matches[i] = -1
+			i++
+		case diffInsert:
+			j++
+		}
+	}
+	return matches
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeLines writes lines to sb unchanged, so that a file without a
+// trailing newline keeps it that way when merged cleanly
+func writeLines(sb *strings.Builder, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(line)
+	}
+}
+
+// writeConflict wraps both versions of a hunk in conflict markers,
+// terminating the line before each marker so markers always start on their
+// own line
+func writeConflict(sb *strings.Builder, ours, theirs []string) {
+	endLine(sb)
+	sb.WriteString(markerExisting)
+	writeLines(sb, ours)
+	endLine(sb)
+	sb.WriteString(markerSplit)
+	writeLines(sb, theirs)
+	endLine(sb)
+	sb.WriteString(markerNew)
+}
+
+// endLine terminates the last line written to sb if it has no newline
+func endLine(sb *strings.Builder) {
+	if out := sb.String(); out != "" && !strings.HasSuffix(out, "\n") {
+		sb.WriteString("\n")
+	}
+}
@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMergeThreeWay(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		existing  string
+		generated string
+		want      string
+		clean     bool
+	}{
+		{
+			name:      "unchanged",
+			base:      "a\nb\nc\n",
+			existing:  "a\nb\nc\n",
+			generated: "a\nb\nc\n",
+			want:      "a\nb\nc\n",
+			clean:     true,
+		},
+		{
+			name:      "only existing changed",
+			base:      "a\nb\nc\n",
+			existing:  "a\nB\nc\n",
+			generated: "a\nb\nc\n",
+			want:      "a\nB\nc\n",
+			clean:     true,
+		},
+		{
+			name:      "only generated changed",
+			base:      "a\nb\nc\n",
+			existing:  "a\nb\nc\n",
+			generated: "a\nb\nC\n",
+			want:      "a\nb\nC\n",
+			clean:     true,
+		},
+		{
+			name:      "both changed different lines",
+			base:      "a\nb\nc\nd\ne\n",
+			existing:  "A\nb\nc\nd\ne\n",
+			generated: "a\nb\nc\nd\nE\n",
+			want:      "A\nb\nc\nd\nE\n",
+			clean:     true,
+		},
+		{
+			name:      "both changed identically",
+			base:      "a\nb\nc\n",
+			existing:  "a\nX\nc\n",
+			generated: "a\nX\nc\n",
+			want:      "a\nX\nc\n",
+			clean:     true,
+		},
+		{
+			name:      "overlapping conflict",
+			base:      "a\nb\nc\n",
+			existing:  "a\nours\nc\n",
+			generated: "a\ntheirs\nc\n",
+			want:      "a\n" + markerExisting + "ours\n" + markerSplit + "theirs\n" + markerNew + "c\n",
+			clean:     false,
+		},
+		{
+			name:      "generated deleted a line",
+			base:      "a\nb\nc\n",
+			existing:  "a\nb\nc\nd\n",
+			generated: "a\nc\n",
+			want:      "a\nc\nd\n",
+			clean:     true,
+		},
+		{
+			name:      "existing deleted a line",
+			base:      "a\nb\nc\n",
+			existing:  "a\nc\n",
+			generated: "a\nb\nc\nd\n",
+			want:      "a\nc\nd\n",
+			clean:     true,
+		},
+		{
+			name:      "no trailing newline kept on clean merge",
+			base:      "a\nb\nc",
+			existing:  "A\nb\nc",
+			generated: "a\nb\nc",
+			want:      "A\nb\nc",
+			clean:     true,
+		},
+		{
+			name:      "no trailing newline on a changed last line",
+			base:      "a\nb\nc",
+			existing:  "A\nb\nc",
+			generated: "a\nb\nC",
+			want:      "A\nb\nC",
+			clean:     true,
+		},
+		{
+			name:      "no trailing newline in conflict",
+			base:      "a\nb",
+			existing:  "a\nours",
+			generated: "a\ntheirs",
+			want:      "a\n" + markerExisting + "ours\n" + markerSplit + "theirs\n" + markerNew,
+			clean:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, clean := mergeThreeWay(tt.base, tt.existing, tt.generated)
+			if got != tt.want || clean != tt.clean {
+				t.Errorf("mergeThreeWay() = %q, %v; want %q, %v", got, clean, tt.want, tt.clean)
+			}
+		})
+	}
+}
+
+// TestMergeThreeWayOneSided checks that a side left equal to the base
+// always yields the other side exactly
+func TestMergeThreeWayOneSided(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	lines := []string{"a\n", "b\n", "c\n", "d\n", "e"}
+	random := func() string {
+		var sb strings.Builder
+		for n := rng.Intn(6); n > 0; n-- {
+			sb.WriteString(lines[rng.Intn(len(lines)-1)])
+		}
+		if rng.Intn(2) == 0 {
+			sb.WriteString(lines[len(lines)-1])
+		}
+		return sb.String()
+	}
+
+	for i := 0; i < 1000; i++ {
+		base, changed := random(), random()
+		if got, clean := mergeThreeWay(base, base, changed); got != changed || !clean {
+			t.Fatalf("mergeThreeWay(%q, base, %q) = %q, %v", base, changed, got, clean)
+		}
+		if got, clean := mergeThreeWay(base, changed, base); got != changed || !clean {
+			t.Fatalf("mergeThreeWay(%q, %q, base) = %q, %v", base, changed, got, clean)
+		}
+	}
+}
+
+func TestMergeTwoWay(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string
+		generated string
+		want      string
+		clean     bool
+	}{
+		{
+			name:      "identical",
+			existing:  "a\nb\n",
+			generated: "a\nb\n",
+			want:      "a\nb\n",
+			clean:     true,
+		},
+		{
+			name:      "lines only in existing are kept",
+			existing:  "a\nmine\nb\n",
+			generated: "a\nb\n",
+			want:      "a\nmine\nb\n",
+			clean:     true,
+		},
+		{
+			name:      "lines only in generated are added",
+			existing:  "a\nb\n",
+			generated: "a\nb\nnew\n",
+			want:      "a\nb\nnew\n",
+			clean:     true,
+		},
+		{
+			name:      "changed lines conflict",
+			existing:  "a\nours\nc\n",
+			generated: "a\ntheirs\nc\n",
+			want:      "a\n" + markerExisting + "ours\n" + markerSplit + "theirs\n" + markerNew + "c\n",
+			clean:     false,
+		},
+		{
+			name:      "no trailing newline kept",
+			existing:  "a\nb",
+			generated: "new\na\nb",
+			want:      "new\na\nb",
+			clean:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, clean := mergeTwoWay(tt.existing, tt.generated)
+			if got != tt.want || clean != tt.clean {
+				t.Errorf("mergeTwoWay() = %q, %v; want %q, %v", got, clean, tt.want, tt.clean)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Layout of a user-supplied template directory
+const (
+	// TemplateManifestFile describes the project type at the directory root
+	TemplateManifestFile = "template.yaml"
+	// templateFilesDir holds the file templates, mirroring the generated layout
+	templateFilesDir = "files"
+)
+
+// TemplateManifest describes a project type loaded from a template directory
+type TemplateManifest struct {
+	Name        string             `yaml:"name"`
+	Description string             `yaml:"description"`
+	Directories []string           `yaml:"directories"`
+	Variables   []TemplateVariable `yaml:"variables"`
+	// Hooks are commands run inside the generated project after writing
+	Hooks []TemplateHook `yaml:"hooks"`
+}
+
+// TemplateVariable is a custom value templates can read as {{.Vars.Name}}
+type TemplateVariable struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Default     string `yaml:"default"`
+	Required    bool   `yaml:"required"`
+}
+
+// CustomTemplate is a complete project type loaded from a local directory
+// or a file system
+type CustomTemplate struct {
+	// Dir is the template directory, or the name given to LoadTemplateFS
+	Dir       string
+	Manifest  TemplateManifest
+	Structure ProjectStructure
+}
+
+// LoadTemplateDir loads a project type from dir. The directory must contain
+// a template.yaml manifest and a files/ directory laid out like the built-in
+// templates: paths mirror the generated project, a trailing .tmpl is
+// stripped, and files starting with an underscore are partials.
+func LoadTemplateDir(dir string) (*CustomTemplate, error) {
+	return LoadTemplateFS(os.DirFS(dir), dir)
+}
+
+// LoadTemplateFS loads a project type laid out like a template directory
+// from fsys, such as an embed.FS compiled into another program. The
+// manifest and the files/ directory must be at the root of fsys; use fs.Sub
+// for a template embedded under a subdirectory. name identifies the template
+// in messages and defaults the manifest name.
+func LoadTemplateFS(fsys fs.FS, name string) (*CustomTemplate, error) {
+	data, err := fs.ReadFile(fsys, TemplateManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template manifest: %w", err)
+	}
+
+	var manifest TemplateManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", TemplateManifestFile, err)
+	}
+
+	if manifest.Name == "" {
+		manifest.Name = filepath.Base(name)
+	}
+
+	for _, v := range manifest.Variables {
+		if v.Name == "" {
+			return nil, fmt.Errorf("%s: every variable needs a name", TemplateManifestFile)
+		}
+	}
+
+	for _, h := range manifest.Hooks {
+		if strings.TrimSpace(h.Run) == "" {
+			return nil, fmt.Errorf("%s: every hook needs a run command", TemplateManifestFile)
+		}
+	}
+
+	if _, err := fs.Stat(fsys, templateFilesDir); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("template directory %s has no %s/ directory", name, templateFilesDir)
+	}
+
+	registry := NewTemplateRegistry(fsys)
+
+	files, err := registry.Files(templateFilesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	partials, err := registry.Partials(templateFilesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CustomTemplate{
+		Dir:      name,
+		Manifest: manifest,
+		Structure: ProjectStructure{
+			Directories: manifest.Directories,
+			Files:       files,
+			Partials:    partials,
+		},
+	}, nil
+}
+
+// resolveVars fills in defaults for unset variables and reports any
+// required variable that is still missing
+func (t *CustomTemplate) resolveVars(vars map[string]string) (map[string]string, error) {
+	return resolveVars(t.Manifest.Name, t.Manifest.Variables, vars)
+}
+
+// resolveVars applies the declared variables of a project type or
+// template named name to the values set by the user
+func resolveVars(name string, declared []TemplateVariable, vars map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(vars))
+	for k, v := range vars {
+		resolved[k] = v
+	}
+
+	var missing []string
+	for _, v := range declared {
+		if _, ok := resolved[v.Name]; ok {
+			continue
+		}
+		if v.Required {
+			missing = append(missing, v.Name)
+			continue
+		}
+		resolved[v.Name] = v.Default
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("template '%s' requires variable(s): %s (set them with -var name=value)",
+			name, strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Origins recorded for values that InferDefaults fills in
+const (
+	OriginDefault   = "default"
+	OriginGitConfig = "git config"
+	OriginGoEnv     = "go env GOVERSION"
+)
+
+// DefaultGoVersion is used when no Go version can be inferred
+const DefaultGoVersion = "1.24"
+
+// InferDefaults fills empty Author, Module and GoVersion fields from the
+// environment and records in config.Origins where each value came from:
+//
+//   - Author from git config user.name and user.email
+//   - Module as modulePrefix/Name when a prefix is configured
+//   - GoVersion from the go directive of a go.work in a parent directory,
+//     then from the installed toolchain, then DefaultGoVersion
+func InferDefaults(config *ProjectConfig, modulePrefix string) {
+	if config.Origins == nil {
+		config.Origins = make(map[string]string)
+	}
+
+	if config.Author == "" {
+		if author := GitAuthor(config.OutputPath); author != "" {
+			config.Author = author
+			config.Origins["author"] = OriginGitConfig
+		}
+	}
+
+	if config.Module == "" && modulePrefix != "" && config.Name != "" {
+		config.Module = strings.TrimSuffix(modulePrefix, "/") + "/" + config.Name
+		config.Origins["module"] = "module prefix " + modulePrefix
+	}
+
+	if config.GoVersion == "" {
+		if work, version := FindWorkspace(config.OutputPath); version != "" {
+			config.GoVersion = version
+			config.Origins["go_version"] = work
+		} else if version := ToolchainGoVersion(); version != "" {
+			config.GoVersion = version
+			config.Origins["go_version"] = OriginGoEnv
+		} else {
+			config.GoVersion = DefaultGoVersion
+			config.Origins["go_version"] = OriginDefault
+		}
+	}
+}
+
+// GitAuthor returns "user.name <user.email>" from the git configuration
+// that applies in dir, or an empty string when git or user.name is missing
+func GitAuthor(dir string) string {
+	name := gitConfig(dir, "user.name")
+	if name == "" {
+		return ""
+	}
+	if email := gitConfig(dir, "user.email"); email != "" {
+		return name + " <" + email + ">"
+	}
+	return name
+}
+
+func gitConfig(dir, key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		cmd.Dir = dir
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ToolchainGoVersion returns the version of the installed go command without
+// the "go" prefix, or an empty string if it is unavailable or a dev build
+func ToolchainGoVersion() string {
+	out, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return ""
+	}
+
+	version := strings.TrimPrefix(strings.TrimSpace(string(out)), "go")
+	if ValidateGoVersion(version) != nil {
+		return ""
+	}
+	return version
+}
+
+// FindWorkspace looks for a go.work file in dir or any parent directory and
+// returns its path together with its go directive version
+func FindWorkspace(dir string) (path, goVersion string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+
+	for {
+		candidate := filepath.Join(dir, "go.work")
+		if data, err := os.ReadFile(candidate); err == nil {
+			return candidate, goDirective(data)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// goDirective extracts the version from the go line of a go.mod or go.work
+func goDirective(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "go" {
+			return fields[1]
+		}
+	}
+	return ""
+}
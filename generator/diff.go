@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each hunk
+const diffContext = 3
+
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffDelete
+	diffInsert
+)
+
+// diffOp is a single line-level edit between two texts
+type diffOp struct {
+	kind diffKind
+	line string
+}
+
+// splitLines splits text into lines, keeping the trailing newline on each line
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a line-level edit script turning a into b using the
+// longest common subsequence of both inputs
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffDelete, a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffInsert, b[j]})
+	}
+	return ops
+}
+
+// UnifiedDiff returns a unified diff turning oldText into newText, or an empty
+// string when both are identical
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n", oldName))
+	sb.WriteString(fmt.Sprintf("+++ %s\n", newName))
+
+	// Walk the edit script and group changes into hunks with context
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == diffEqual {
+			start++
+			oldLine++
+			newLine++
+		}
+		if start >= len(ops) {
+			break
+		}
+
+		// Extend the hunk until we see more than 2*context equal lines
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != diffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == diffEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				break
+			}
+			end = run
+		}
+
+		lead := min(diffContext, start)
+		trail := 0
+		for end+trail < len(ops) && trail < diffContext && ops[end+trail].kind == diffEqual {
+			trail++
+		}
+
+		hunkStart := start - lead
+		hunkEnd := end + trail
+		oldStart, newStart := oldLine-lead, newLine-lead
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != diffInsert {
+				oldCount++
+			}
+			if op.kind != diffDelete {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount))
+		for _, op := range ops[hunkStart:hunkEnd] {
+			prefix := " "
+			switch op.kind {
+			case diffDelete:
+				prefix = "-"
+			case diffInsert:
+				prefix = "+"
+			}
+			sb.WriteString(prefix)
+			sb.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		// Advance line counters past the hunk body
+		for _, op := range ops[start:end] {
+			if op.kind != diffInsert {
+				oldLine++
+			}
+			if op.kind != diffDelete {
+				newLine++
+			}
+		}
+		start = end
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+package generator
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "identical",
+			oldText: "a\nb\n",
+			newText: "a\nb\n",
+			want:    "",
+		},
+		{
+			name:    "changed line",
+			oldText: "a\nb\nc\n",
+			newText: "a\nB\nc\n",
+			want:    "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:    "new file",
+			oldText: "",
+			newText: "a\n",
+			want:    "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name:    "deleted file",
+			oldText: "a\n",
+			newText: "",
+			want:    "--- old\n+++ new\n@@ -1,1 +0,0 @@\n-a\n",
+		},
+		{
+			name:    "no trailing newline",
+			oldText: "a\nb",
+			newText: "a\nb\n",
+			want:    "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name:    "distant changes make two hunks",
+			oldText: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			newText: "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", tt.oldText, tt.newText); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
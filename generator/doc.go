@@ -0,0 +1,59 @@
+// Package generator renders Go project scaffolds. It backs the go-projo
+// command and can be embedded in other programs.
+//
+// A Generator is created from a ProjectConfig and optional Option values:
+//
+//	gen, err := generator.New(generator.ProjectConfig{
+//		Name:       "billing",
+//		Module:     "github.com/acme/billing",
+//		Type:       generator.ProjectTypeAPI,
+//		OutputPath: "/srv/projects",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//
+// It can then produce the project in several ways:
+//
+//   - Render returns every directory and file in memory
+//   - Plan compares the rendered files with the output directory
+//   - Write sends the project to a Sink: NewDiskSink, NewMemorySink or
+//     NewArchiveSink for .tar.gz and .zip archives
+//   - Generate writes to OutputPath/Name through a staging directory
+//
+// RunHooks formats the written project, runs go mod tidy and git init, and
+// runs any commands declared by a custom template.
+//
+// The project type is one of the built-in types, a template directory
+// (ProjectConfig.TemplateDir), a template loaded from any fs.FS with
+// LoadTemplateFS and passed with WithTemplate, or a ProjectStructure defined
+// in code with WithStructure. WithFile and WithPartial add or replace
+// individual templates:
+//
+//	//go:embed all:acme-service
+//	var acmeTemplates embed.FS
+//
+//	// The embedded files sit under acme-service/, while LoadTemplateFS
+//	// expects template.yaml at the root of the FS it is given
+//	sub, err := fs.Sub(acmeTemplates, "acme-service")
+//	...
+//	tmpl, err := generator.LoadTemplateFS(sub, "acme-service")
+//	...
+//	gen, err := generator.New(config,
+//		generator.WithTemplate(tmpl),
+//		generator.WithFile("CODEOWNERS", "* @acme/{{.Vars.Team}}\n"),
+//	)
+//
+// ProjectConfig.Features layers registered add-ons such as "postgres" or
+// "otel" on top of the project type; RegisterFeature adds more.
+//
+// Generated projects record their settings in a ManifestFile, which
+// OpenProject reads to Upgrade the project, and InspectProject uses to run
+// Doctor checks.
+//
+// Failures are reported with typed errors that can be inspected with
+// errors.As: *ValidationError for an invalid ProjectConfig, *TemplateError
+// for a template that does not parse or execute, *GoSourceError for
+// rendered Go code that is not valid, and *ConflictError when existing
+// files block generation under ConflictFail.
+package generator
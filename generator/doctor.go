@@ -0,0 +1,301 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks doctor findings
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// ParseSeverity converts a flag value into a Severity
+func ParseSeverity(s string) (Severity, error) {
+	switch Severity(s) {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return Severity(s), nil
+	}
+	return "", fmt.Errorf("invalid severity '%s'. Must be one of: error, warning, info", s)
+}
+
+// AtLeast reports whether s is as severe as other or more
+func (s Severity) AtLeast(other Severity) bool {
+	rank := map[Severity]int{SeverityInfo: 0, SeverityWarning: 1, SeverityError: 2}
+	return rank[s] >= rank[other]
+}
+
+// Finding is a single problem doctor found in a project
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// DoctorReport lists how a project deviates from the layout of its type
+type DoctorReport struct {
+	BasePath string      `json:"base_path"`
+	Type     ProjectType `json:"type"`
+	Findings []Finding   `json:"findings"`
+}
+
+// Count returns the number of findings with exactly the given severity
+func (r *DoctorReport) Count(severity Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed reports whether any finding is at least as severe as threshold
+func (r *DoctorReport) Failed(threshold Severity) bool {
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// Doctor checks the project directory against what the project type
+// generates: its directories, required files, the module path and Go
+// version in go.mod, and the Makefile targets. Missing Go sources, go.mod
+// and Makefile are errors; other missing files and directories are
+// warnings.
+func (g *Generator) Doctor() (*DoctorReport, error) {
+	basePath := g.basePath()
+	if info, err := os.Stat(basePath); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a project directory", basePath)
+	}
+
+	r, err := g.render()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DoctorReport{
+		BasePath: basePath,
+		Type:     g.config.Type,
+		Findings: []Finding{},
+	}
+	add := func(check string, severity Severity, p, format string, args ...interface{}) {
+		report.Findings = append(report.Findings, Finding{check, severity, p, fmt.Sprintf(format, args...)})
+	}
+
+	if _, err := os.Stat(filepath.Join(basePath, ManifestFile)); err != nil {
+		add("manifest", SeverityInfo, ManifestFile, "no %s; checked against the %s layout using go.mod", ManifestFile, g.config.Type)
+	}
+
+	for _, dir := range r.directories {
+		if info, err := os.Stat(filepath.Join(basePath, dir)); err != nil || !info.IsDir() {
+			add("directories", SeverityWarning, dir, "directory %s is missing", dir)
+		}
+	}
+
+	var makefile string
+	for _, file := range r.files {
+		if file.path == "Makefile" {
+			makefile = file.content
+		}
+		if _, err := os.Stat(filepath.Join(basePath, file.path)); errors.Is(err, fs.ErrNotExist) {
+			add("files", requiredFileSeverity(file.path), file.path, "file %s is missing", file.path)
+		}
+	}
+
+	g.checkGoMod(basePath, add)
+
+	if makefile != "" {
+		checkMakefile(basePath, makefile, add)
+	}
+
+	return report, nil
+}
+
+// requiredFileSeverity ranks a missing file: the project cannot build or
+// run without its Go sources, go.mod or Makefile
+func requiredFileSeverity(p string) Severity {
+	if strings.HasSuffix(p, ".go") || p == "go.mod" || p == "Makefile" {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// checkGoMod compares the module path and go directive with the config
+func (g *Generator) checkGoMod(basePath string, add func(check string, severity Severity, p, format string, args ...interface{})) {
+	data, err := os.ReadFile(filepath.Join(basePath, "go.mod"))
+	if err != nil {
+		// A missing go.mod is already reported as a missing file
+		return
+	}
+
+	module, goVersion := ReadGoMod(data)
+	switch {
+	case module == "":
+		add("go.mod", SeverityError, "go.mod", "go.mod has no module directive")
+	case module != g.config.Module:
+		add("go.mod", SeverityError, "go.mod", "module is %s, expected %s", module, g.config.Module)
+	}
+
+	switch {
+	case goVersion == "":
+		add("go.mod", SeverityError, "go.mod", "go.mod has no go directive")
+	case ValidateGoVersion(goVersion) != nil:
+		add("go.mod", SeverityError, "go.mod", "go directive %s is not a valid Go version", goVersion)
+	case compareGoVersions(goVersion, g.config.GoVersion) < 0:
+		add("go.mod", SeverityWarning, "go.mod", "go directive is %s, older than the expected %s", goVersion, g.config.GoVersion)
+	case goVersion != g.config.GoVersion:
+		add("go.mod", SeverityInfo, "go.mod", "go directive is %s, expected %s", goVersion, g.config.GoVersion)
+	}
+}
+
+// makeTarget matches a rule line of a Makefile
+var makeTarget = regexp.MustCompile(`^([A-Za-z0-9_.-]+)\s*:([^=]|$)`)
+
+// checkMakefile reports targets of the generated Makefile missing on disk
+func checkMakefile(basePath, generated string, add func(check string, severity Severity, p, format string, args ...interface{})) {
+	data, err := os.ReadFile(filepath.Join(basePath, "Makefile"))
+	if err != nil {
+		return
+	}
+
+	have := makefileTargets(string(data))
+	for _, target := range sortedKeys(makefileTargets(generated)) {
+		if !have[target] {
+			add("makefile", SeverityWarning, "Makefile", "target %s is missing", target)
+		}
+	}
+}
+
+// makefileTargets returns the rule names defined in a Makefile
+func makefileTargets(content string) map[string]bool {
+	targets := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		if m := makeTarget.FindStringSubmatch(scanner.Text()); m != nil && !strings.HasPrefix(m[1], ".") {
+			targets[m[1]] = true
+		}
+	}
+	return targets
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReadGoMod extracts the module path and go directive from go.mod content
+func ReadGoMod(data []byte) (module, goVersion string) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "module" {
+			module = fields[1]
+			if unquoted, err := strconv.Unquote(module); err == nil {
+				module = unquoted
+			}
+		}
+	}
+	return module, goDirective(data)
+}
+
+// compareGoVersions orders two Go versions the way the go command does: a
+// language version such as 1.22 comes before its prereleases, betas before
+// release candidates, and those before releases such as 1.22.0 and 1.22.3
+func compareGoVersions(a, b string) int {
+	va, vb := parseGoVersion(a), parseGoVersion(b)
+	for i := range va {
+		if va[i] != vb[i] {
+			if va[i] < vb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Kinds of Go version, in release order
+const (
+	goVersionLanguage = iota
+	goVersionBeta
+	goVersionRC
+	goVersionRelease
+)
+
+// parseGoVersion splits a Go version into major, minor, kind and the beta,
+// rc or patch number. Missing or malformed numbers count as zero.
+func parseGoVersion(v string) [4]int {
+	major, rest, _ := strings.Cut(v, ".")
+	minor, kind, num := rest, goVersionLanguage, ""
+	if m, patch, ok := strings.Cut(rest, "."); ok {
+		minor, kind, num = m, goVersionRelease, patch
+	} else if m, pre, ok := strings.Cut(rest, "rc"); ok {
+		minor, kind, num = m, goVersionRC, pre
+	} else if m, pre, ok := strings.Cut(rest, "beta"); ok {
+		minor, kind, num = m, goVersionBeta, pre
+	}
+
+	var parsed [4]int
+	parsed[0], _ = strconv.Atoi(major)
+	parsed[1], _ = strconv.Atoi(minor)
+	parsed[2] = kind
+	parsed[3], _ = strconv.Atoi(num)
+	return parsed
+}
+
+// InspectProject returns a Generator for checking the project in dir. The
+// settings come from the project manifest when there is one; otherwise the
+// module path and Go version are read from go.mod and projectType is used.
+func InspectProject(dir string, projectType ProjectType) (*Generator, error) {
+	if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err == nil {
+		g, _, err := OpenProject(dir, "")
+		return g, err
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("%s has neither %s nor a readable go.mod: %w", dir, ManifestFile, err)
+	}
+	module, goVersion := ReadGoMod(data)
+	if module == "" {
+		return nil, fmt.Errorf("go.mod in %s has no module directive", dir)
+	}
+	if ValidateGoVersion(goVersion) != nil {
+		goVersion = ""
+	}
+
+	return New(ProjectConfig{
+		Name:       path.Base(filepath.ToSlash(dir)),
+		Module:     module,
+		Type:       projectType,
+		GoVersion:  goVersion,
+		OutputPath: filepath.Dir(dir),
+	}, WithProjectDir(dir))
+}
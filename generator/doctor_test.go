@@ -0,0 +1,36 @@
+package generator
+
+import "testing"
+
+func TestCompareGoVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.22", "1.22", 0},
+		{"1.22.3", "1.22.3", 0},
+		{"1.21", "1.22", -1},
+		{"1.9", "1.10", -1},
+		{"2.0", "1.30", 1},
+		{"1.22", "1.22.0", -1},
+		{"1.22.0", "1.22.1", -1},
+		{"1.22.10", "1.22.9", 1},
+		{"1.21rc1", "1.21rc2", -1},
+		{"1.21rc2", "1.21rc10", -1},
+		{"1.21rc1", "1.21.0", -1},
+		{"1.21rc1", "1.21", 1},
+		{"1.21beta1", "1.21rc1", -1},
+		{"1.21beta2", "1.21beta1", 1},
+		{"1.21rc1", "1.20.14", 1},
+		{"1.22rc1", "1.21.9", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareGoVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareGoVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareGoVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareGoVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
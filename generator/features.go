@@ -0,0 +1,358 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Feature is an optional add-on selected with -with and layered on top of a
+// project type. Besides its own directories and files, a feature feeds the
+// type's templates through the template data: go.mod requirements, config
+// fields, Makefile targets and setup code wired into main.
+type Feature struct {
+	Name string
+	// Description is a one-line summary shown in help and listings
+	Description string
+	// Types limits the feature to these project types; empty allows every
+	// type whose templates use the feature data
+	Types []ProjectType
+	// Requires lists features enabled automatically along with this one
+	Requires []string
+	// RequiresOneOf lists features at least one of which must be selected
+	RequiresOneOf []string
+	// Conflicts lists features that cannot be combined with this one
+	Conflicts []string
+
+	Directories []string
+	// Files are templates keyed by output path, rendered like the files of
+	// the project type
+	Files map[string]string
+	// Modules are added to the require block of go.mod
+	Modules []ModuleRequirement
+	// ConfigFields are added to the Config struct and loaded from the
+	// environment
+	ConfigFields []ConfigField
+	// MakeTargets are appended to the Makefile
+	MakeTargets []MakeTarget
+	// Imports are packages of the generated module, relative to its root,
+	// imported by main for Setup
+	Imports []string
+	// Setup is Go code run in main once the configuration is loaded. It can
+	// use cfg, err, and the variables set up by the features before it.
+	Setup string
+}
+
+// ModuleRequirement is a require line of go.mod
+type ModuleRequirement struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// ConfigField is a string field of the generated Config struct
+type ConfigField struct {
+	// Name is the Go field name
+	Name string `json:"name"`
+	// Env is the environment variable the field is loaded from
+	Env     string `json:"env"`
+	Default string `json:"default"`
+}
+
+// MakeTarget is a Makefile target contributed by a feature
+type MakeTarget struct {
+	Name string `json:"name"`
+	// Help is the one-line description printed by make help
+	Help   string   `json:"help"`
+	Recipe []string `json:"recipe"`
+}
+
+// featureRegistry holds every registered feature in registration order,
+// which is also the order their Setup code runs in
+var featureRegistry = struct {
+	sync.RWMutex
+	order    []string
+	features map[string]*Feature
+}{
+	features: make(map[string]*Feature),
+}
+
+// RegisterFeature adds a feature. Names must be unique, and every feature
+// named by Requires, RequiresOneOf or Conflicts must already be registered.
+// Conflicts are symmetric: the features named are marked as conflicting
+// with f too.
+func RegisterFeature(f Feature) error {
+	if f.Name == "" {
+		return fmt.Errorf("feature needs a name")
+	}
+	for _, c := range f.ConfigFields {
+		if c.Name == "" || c.Env == "" {
+			return fmt.Errorf("feature %s: every config field needs a name and an environment variable", f.Name)
+		}
+	}
+
+	featureRegistry.Lock()
+	defer featureRegistry.Unlock()
+
+	if _, ok := featureRegistry.features[f.Name]; ok {
+		return fmt.Errorf("feature %s is already registered", f.Name)
+	}
+	for _, list := range [][]string{f.Requires, f.RequiresOneOf, f.Conflicts} {
+		for _, name := range list {
+			if _, ok := featureRegistry.features[name]; !ok {
+				return fmt.Errorf("feature %s refers to unknown feature %s", f.Name, name)
+			}
+		}
+	}
+
+	f.Conflicts = append([]string(nil), f.Conflicts...)
+	for _, name := range f.Conflicts {
+		other := featureRegistry.features[name]
+		other.Conflicts = append(append([]string(nil), other.Conflicts...), f.Name)
+	}
+
+	featureRegistry.features[f.Name] = &f
+	featureRegistry.order = append(featureRegistry.order, f.Name)
+	return nil
+}
+
+// MustRegisterFeature is RegisterFeature for use in init functions; it
+// panics on error
+func MustRegisterFeature(f Feature) {
+	if err := RegisterFeature(f); err != nil {
+		panic(err)
+	}
+}
+
+// LookupFeature finds a registered feature by name
+func LookupFeature(name string) (Feature, bool) {
+	featureRegistry.RLock()
+	defer featureRegistry.RUnlock()
+
+	f, ok := featureRegistry.features[name]
+	if !ok {
+		return Feature{}, false
+	}
+	return *f, true
+}
+
+// Features returns every registered feature in registration order
+func Features() []Feature {
+	featureRegistry.RLock()
+	defer featureRegistry.RUnlock()
+
+	features := make([]Feature, 0, len(featureRegistry.order))
+	for _, name := range featureRegistry.order {
+		features = append(features, *featureRegistry.features[name])
+	}
+	return features
+}
+
+// FeatureNames returns the names of every registered feature
+func FeatureNames() []string {
+	var names []string
+	for _, f := range Features() {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// FeaturesFor returns the features available to a project type
+func FeaturesFor(t ProjectType) []Feature {
+	var features []Feature
+	for _, f := range Features() {
+		if f.Supports(t) {
+			features = append(features, f)
+		}
+	}
+	return features
+}
+
+// Supports reports whether the feature can be added to project type t
+func (f Feature) Supports(t ProjectType) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, allowed := range f.Types {
+		if allowed == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFeatures checks a list of feature names
+func ParseFeatures(names []string) ([]string, error) {
+	for _, name := range names {
+		if _, ok := LookupFeature(name); !ok {
+			return nil, fmt.Errorf("unknown feature '%s'. Must be one of: %s", name, strings.Join(FeatureNames(), ", "))
+		}
+	}
+	return names, nil
+}
+
+// FeatureHelp formats the registered features as an aligned two-column list
+// for help output, each line starting with indent
+func FeatureHelp(indent string) string {
+	features := Features()
+
+	width := 0
+	for _, f := range features {
+		width = max(width, len(f.Name))
+	}
+
+	var sb strings.Builder
+	for _, f := range features {
+		desc := f.Description
+		if len(f.Types) > 0 {
+			types := make([]string, len(f.Types))
+			for i, t := range f.Types {
+				types[i] = string(t)
+			}
+			desc += fmt.Sprintf(" (%s)", strings.Join(types, ", "))
+		}
+		fmt.Fprintf(&sb, "%s%-*s  %s\n", indent, width, f.Name, desc)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// ResolveFeatures expands names with the features they require and checks
+// them against project type t and each other. The result is in
+// registration order.
+func ResolveFeatures(t ProjectType, names []string) ([]Feature, error) {
+	if _, err := ParseFeatures(names); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool)
+	var add func(name string)
+	add = func(name string) {
+		if selected[name] {
+			return
+		}
+		selected[name] = true
+		f, _ := LookupFeature(name)
+		for _, req := range f.Requires {
+			add(req)
+		}
+	}
+	for _, name := range names {
+		add(name)
+	}
+
+	var features []Feature
+	for _, f := range Features() {
+		if !selected[f.Name] {
+			continue
+		}
+
+		if !f.Supports(t) {
+			types := make([]string, len(f.Types))
+			for i, allowed := range f.Types {
+				types[i] = string(allowed)
+			}
+			return nil, fmt.Errorf("feature %s is not available for project type %s; it supports %s",
+				f.Name, t, strings.Join(types, ", "))
+		}
+
+		for _, other := range f.Conflicts {
+			if selected[other] {
+				return nil, fmt.Errorf("feature %s cannot be combined with %s", f.Name, other)
+			}
+		}
+
+		if len(f.RequiresOneOf) > 0 {
+			found := false
+			for _, other := range f.RequiresOneOf {
+				found = found || selected[other]
+			}
+			if !found {
+				return nil, fmt.Errorf("feature %s requires one of: %s", f.Name, strings.Join(f.RequiresOneOf, ", "))
+			}
+		}
+
+		features = append(features, f)
+	}
+
+	return features, nil
+}
+
+// applyFeatures adds the directories and files of the selected features to
+// the structure and records their canonical names in the config
+func (g *Generator) applyFeatures() error {
+	if len(g.config.Features) == 0 {
+		return nil
+	}
+
+	features, err := ResolveFeatures(g.config.Type, g.config.Features)
+	if err != nil {
+		return err
+	}
+
+	g.structure.Files = cloneTemplates(g.structure.Files)
+	g.structure.Directories = append([]string(nil), g.structure.Directories...)
+	owners := make(map[string]string)
+	names := make([]string, 0, len(features))
+	for _, f := range features {
+		g.structure.Directories = append(g.structure.Directories, f.Directories...)
+		for p, content := range f.Files {
+			if _, ok := g.structure.Files[p]; ok {
+				if owner := owners[p]; owner != "" {
+					return fmt.Errorf("features %s and %s both add %s", owner, f.Name, p)
+				}
+				return fmt.Errorf("feature %s adds %s, which project type %s already has", f.Name, p, g.config.Type)
+			}
+			g.structure.Files[p] = content
+			owners[p] = f.Name
+		}
+		names = append(names, f.Name)
+	}
+
+	g.features = features
+	g.config.Features = names
+	return nil
+}
+
+// featureData collects what the selected features contribute to the
+// templates of the project type
+type featureData struct {
+	// Modules are the go.mod requirements, sorted by path
+	Modules []ModuleRequirement
+	// ConfigFields are the extra Config fields
+	ConfigFields []ConfigField
+	// MakeTargets are the extra Makefile targets
+	MakeTargets []MakeTarget
+	// Imports are the module-relative packages main imports for Setup
+	Imports []string
+	// Setup holds the setup code of each feature, in order
+	Setup []string
+}
+
+// featureData merges the contributions of the selected features
+func (g *Generator) featureData() featureData {
+	var d featureData
+	modules := make(map[string]string)
+	imports := make(map[string]bool)
+	for _, f := range g.features {
+		for _, m := range f.Modules {
+			modules[m.Path] = m.Version
+		}
+		for _, imp := range f.Imports {
+			if !imports[imp] {
+				imports[imp] = true
+				d.Imports = append(d.Imports, imp)
+			}
+		}
+		d.ConfigFields = append(d.ConfigFields, f.ConfigFields...)
+		d.MakeTargets = append(d.MakeTargets, f.MakeTargets...)
+		if f.Setup != "" {
+			d.Setup = append(d.Setup, strings.TrimSpace(f.Setup))
+		}
+	}
+
+	for p, version := range modules {
+		d.Modules = append(d.Modules, ModuleRequirement{Path: p, Version: version})
+	}
+	sort.Slice(d.Modules, func(i, j int) bool { return d.Modules[i].Path < d.Modules[j].Path })
+	return d
+}
@@ -0,0 +1,244 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// restoreFeatures puts the feature registry back as it is now when the
+// test ends. Features are copied, since registering a conflict updates the
+// feature it conflicts with.
+func restoreFeatures(t *testing.T) {
+	t.Helper()
+	featureRegistry.Lock()
+	order := append([]string(nil), featureRegistry.order...)
+	features := make(map[string]*Feature, len(featureRegistry.features))
+	for name, f := range featureRegistry.features {
+		saved := *f
+		features[name] = &saved
+	}
+	featureRegistry.Unlock()
+
+	t.Cleanup(func() {
+		featureRegistry.Lock()
+		defer featureRegistry.Unlock()
+		featureRegistry.order, featureRegistry.features = order, features
+	})
+}
+
+func TestRegisterFeature(t *testing.T) {
+	restoreFeatures(t)
+
+	tests := []struct {
+		name    string
+		feature Feature
+		wantErr string
+	}{
+		{
+			name:    "new feature",
+			feature: Feature{Name: "queue", Requires: []string{"redis"}, Conflicts: []string{"otel"}},
+		},
+		{
+			name:    "duplicate name",
+			feature: Feature{Name: "queue"},
+			wantErr: "feature queue is already registered",
+		},
+		{
+			name:    "no name",
+			feature: Feature{},
+			wantErr: "feature needs a name",
+		},
+		{
+			name:    "unknown requirement",
+			feature: Feature{Name: "cache", Requires: []string{"memcached"}},
+			wantErr: "feature cache refers to unknown feature memcached",
+		},
+		{
+			name:    "unknown alternative",
+			feature: Feature{Name: "cache", RequiresOneOf: []string{"redis", "memcached"}},
+			wantErr: "feature cache refers to unknown feature memcached",
+		},
+		{
+			name:    "unknown conflict",
+			feature: Feature{Name: "cache", Conflicts: []string{"memcached"}},
+			wantErr: "feature cache refers to unknown feature memcached",
+		},
+		{
+			name:    "incomplete config field",
+			feature: Feature{Name: "cache", ConfigFields: []ConfigField{{Name: "CacheURL"}}},
+			wantErr: "every config field needs a name and an environment variable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterFeature(tt.feature)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("RegisterFeature() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RegisterFeature() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, ok := LookupFeature("cache"); ok {
+		t.Errorf("a rejected feature was registered")
+	}
+
+	// Conflicts are recorded on both sides
+	otel, _ := LookupFeature("otel")
+	if !reflect.DeepEqual(otel.Conflicts, []string{"queue"}) {
+		t.Errorf("otel conflicts = %v, want [queue]", otel.Conflicts)
+	}
+}
+
+func TestResolveFeatures(t *testing.T) {
+	restoreFeatures(t)
+	MustRegisterFeature(Feature{Name: "queue", Requires: []string{"redis"}})
+	MustRegisterFeature(Feature{Name: "worker", Requires: []string{"queue", "otel"}})
+	MustRegisterFeature(Feature{Name: "tracing-lite", Conflicts: []string{"otel"}})
+	MustRegisterFeature(Feature{Name: "cli-only", Types: []ProjectType{ProjectTypeCLI}})
+
+	tests := []struct {
+		name     string
+		typ      ProjectType
+		features []string
+		// want lists the resolved features in registration order
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "nothing selected",
+			typ:  ProjectTypeAPI,
+		},
+		{
+			name:     "single feature",
+			typ:      ProjectTypeAPI,
+			features: []string{"redis"},
+			want:     []string{"redis"},
+		},
+		{
+			name:     "registration order",
+			typ:      ProjectTypeMicro,
+			features: []string{"redis", "otel"},
+			want:     []string{"otel", "redis"},
+		},
+		{
+			name:     "required feature added",
+			typ:      ProjectTypeAPI,
+			features: []string{"queue"},
+			want:     []string{"redis", "queue"},
+		},
+		{
+			name:     "requirements expanded transitively",
+			typ:      ProjectTypeAPI,
+			features: []string{"worker"},
+			want:     []string{"otel", "redis", "queue", "worker"},
+		},
+		{
+			name:     "duplicates collapsed",
+			typ:      ProjectTypeAPI,
+			features: []string{"queue", "redis", "queue"},
+			want:     []string{"redis", "queue"},
+		},
+		{
+			name:     "requires one of satisfied",
+			typ:      ProjectTypeAPI,
+			features: []string{"migrate", "mysql"},
+			want:     []string{"mysql", "migrate"},
+		},
+		{
+			name:     "requires one of missing",
+			typ:      ProjectTypeAPI,
+			features: []string{"migrate"},
+			wantErr:  "feature migrate requires one of: postgres, mysql",
+		},
+		{
+			name:     "conflict",
+			typ:      ProjectTypeAPI,
+			features: []string{"mysql", "postgres"},
+			wantErr:  "feature postgres cannot be combined with mysql",
+		},
+		{
+			name:     "conflict through a requirement",
+			typ:      ProjectTypeAPI,
+			features: []string{"worker", "tracing-lite"},
+			wantErr:  "feature otel cannot be combined with tracing-lite",
+		},
+		{
+			name:     "unsupported type",
+			typ:      ProjectTypeLibrary,
+			features: []string{"postgres"},
+			wantErr:  "feature postgres is not available for project type library; it supports api, microservice",
+		},
+		{
+			name:     "type limited feature",
+			typ:      ProjectTypeCLI,
+			features: []string{"cli-only"},
+			want:     []string{"cli-only"},
+		},
+		{
+			name:     "unknown feature",
+			typ:      ProjectTypeAPI,
+			features: []string{"kafka"},
+			wantErr:  "unknown feature 'kafka'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			features, err := ResolveFeatures(tt.typ, tt.features)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveFeatures() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveFeatures() error = %v", err)
+			}
+
+			var got []string
+			for _, f := range features {
+				got = append(got, f.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveFeatures(%v) = %v, want %v", tt.features, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFeatures(t *testing.T) {
+	g, err := New(ProjectConfig{
+		Name:     "myapi",
+		Module:   "github.com/user/myapi",
+		Type:     ProjectTypeAPI,
+		Features: []string{"migrate", "postgres"},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got := g.Config().Features; !reflect.DeepEqual(got, []string{"postgres", "migrate"}) {
+		t.Errorf("Features = %v, want the canonical order [postgres migrate]", got)
+	}
+
+	data := g.templateData()
+	if !data.HasFeature("postgres") || data.HasFeature("mysql") {
+		t.Errorf("HasFeature does not match the selected features")
+	}
+	for i := 1; i < len(data.Modules); i++ {
+		if data.Modules[i-1].Path >= data.Modules[i].Path {
+			t.Errorf("Modules are not sorted by path: %v", data.Modules)
+		}
+	}
+	if len(data.Setup) != 2 {
+		t.Errorf("Setup has %d entries, want one per feature", len(data.Setup))
+	}
+}
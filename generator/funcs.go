@@ -0,0 +1,409 @@
+package generator
+
+import (
+	"go/token"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// templateData is the value templates are executed with. It embeds the
+// ProjectConfig so existing fields like {{.Name}} keep working, and adds
+// values derived from it.
+type templateData struct {
+	ProjectConfig
+	// featureData holds what the selected features add to go.mod, the
+	// config, the Makefile and main
+	featureData
+	// PackageName is Name sanitized into a valid Go package name
+	PackageName string
+	// BinaryName is Name in kebab-case, used for binaries and images
+	BinaryName string
+	// Year is the current year, for license and copyright headers
+	Year int
+}
+
+// TemplateField is a value of the data every template is executed with
+type TemplateField struct {
+	// Name is the field as written in a template, such as .Module
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// TemplateFields lists the standard template data, common to every project
+// type; template variables are available under .Vars
+func TemplateFields() []TemplateField {
+	return []TemplateField{
+		{".Name", "Project name"},
+		{".Module", "Go module path"},
+		{".Type", "Project type"},
+		{".PackageName", "Name as a valid Go package name"},
+		{".BinaryName", "Name in kebab-case, used for binaries and images"},
+		{".Description", "Project description"},
+		{".Author", "Author name"},
+		{".License", "License named in the README"},
+		{".GoVersion", "Go version of go.mod"},
+		{".Year", "Current year"},
+		{".Vars", "Template variables, set with -var name=value"},
+		{".Features", "Selected features"},
+		{`.HasFeature "name"`, "Whether the named feature was selected"},
+	}
+}
+
+// templateData builds the data passed to every template
+func (g *Generator) templateData() templateData {
+	return templateData{
+		ProjectConfig: g.config,
+		featureData:   g.featureData(),
+		PackageName:   toPackageName(g.config.Name),
+		BinaryName:    toKebab(g.config.Name),
+		Year:          time.Now().Year(),
+	}
+}
+
+// HasFeature reports whether the named feature was selected, for
+// {{if .HasFeature "postgres"}}
+func (d templateData) HasFeature(name string) bool {
+	for _, f := range d.Features {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// templateFuncs returns the helper functions available to every template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// Case conversion
+		"lower":     strings.ToLower,
+		"upper":     strings.ToUpper,
+		"camel":     toCamel,
+		"pascal":    toPascal,
+		"snake":     toSnake,
+		"kebab":     toKebab,
+		"screaming": toScreaming,
+
+		// Go identifiers
+		"goIdent":     toGoIdent,
+		"packageName": toPackageName,
+
+		// Inflection
+		"pluralize":   pluralize,
+		"singularize": singularize,
+
+		// Dates
+		"year": func() int { return time.Now().Year() },
+		"date": func(layout ...string) string {
+			if len(layout) > 0 {
+				return time.Now().Format(layout[0])
+			}
+			return time.Now().Format("2006-01-02")
+		},
+
+		// Module paths
+		"moduleBase":   moduleBase,
+		"moduleParent": moduleParent,
+		"modulePath":   modulePath,
+	}
+}
+
+// splitWords breaks s into words on punctuation, spaces and case changes,
+// keeping acronyms together: "myHTTPServer-v2" becomes my, HTTP, Server, v2
+func splitWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	runes := []rune(s)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+func capitalize(w string) string {
+	runes := []rune(strings.ToLower(w))
+	if len(runes) == 0 {
+		return ""
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// toPascal converts s to PascalCase: "my-lib" becomes "MyLib"
+func toPascal(s string) string {
+	var sb strings.Builder
+	for _, w := range splitWords(s) {
+		sb.WriteString(capitalize(w))
+	}
+	return sb.String()
+}
+
+// toCamel converts s to camelCase: "my-lib" becomes "myLib"
+func toCamel(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(words[0]))
+	for _, w := range words[1:] {
+		sb.WriteString(capitalize(w))
+	}
+	return sb.String()
+}
+
+func joinWords(s, sep string, transform func(string) string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = transform(w)
+	}
+	return strings.Join(words, sep)
+}
+
+// toSnake converts s to snake_case: "MyLib" becomes "my_lib"
+func toSnake(s string) string {
+	return joinWords(s, "_", strings.ToLower)
+}
+
+// toKebab converts s to kebab-case: "MyLib" becomes "my-lib"
+func toKebab(s string) string {
+	return joinWords(s, "-", strings.ToLower)
+}
+
+// toScreaming converts s to SCREAMING_SNAKE_CASE: "my-lib" becomes "MY_LIB"
+func toScreaming(s string) string {
+	return joinWords(s, "_", strings.ToUpper)
+}
+
+// toGoIdent converts s into a valid camelCase Go identifier: "my-lib"
+// becomes "myLib", "2fa" becomes "_2fa"
+func toGoIdent(s string) string {
+	ident := toCamel(s)
+	if ident == "" {
+		return "_"
+	}
+	if unicode.IsDigit([]rune(ident)[0]) || token.IsKeyword(ident) {
+		ident = "_" + ident
+	}
+	return ident
+}
+
+// toPackageName converts s into a conventional Go package name: lower case
+// letters and digits only, not starting with a digit and not a keyword.
+// "my-lib" becomes "mylib".
+func toPackageName(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(path.Base(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+
+	name := sb.String()
+	switch {
+	case name == "":
+		return "pkg"
+	case unicode.IsDigit([]rune(name)[0]):
+		return "pkg" + name
+	case token.IsKeyword(name):
+		return name + "pkg"
+	}
+	return name
+}
+
+// irregularPlurals maps common irregular singular nouns to their plurals
+var irregularPlurals = map[string]string{
+	"child":  "children",
+	"person": "people",
+	"man":    "men",
+	"woman":  "women",
+	"mouse":  "mice",
+	"goose":  "geese",
+	"foot":   "feet",
+	"tooth":  "teeth",
+	"datum":  "data",
+	"index":  "indices",
+}
+
+// vesPlurals are the nouns ending in f or fe whose plural ends in ves;
+// every other such noun just takes an s (chef, roof, belief)
+var vesPlurals = map[string]string{
+	"calf":  "calves",
+	"elf":   "elves",
+	"half":  "halves",
+	"knife": "knives",
+	"leaf":  "leaves",
+	"life":  "lives",
+	"loaf":  "loaves",
+	"self":  "selves",
+	"shelf": "shelves",
+	"thief": "thieves",
+	"wife":  "wives",
+	"wolf":  "wolves",
+}
+
+// ieSingulars are nouns ending in ie, whose plural ends in ies like that of
+// nouns ending in y
+var ieSingulars = map[string]bool{
+	"calorie": true,
+	"cookie":  true,
+	"genie":   true,
+	"lie":     true,
+	"movie":   true,
+	"pie":     true,
+	"rookie":  true,
+	"selfie":  true,
+	"tie":     true,
+	"zombie":  true,
+}
+
+// uncountable words have the same singular and plural form
+var uncountable = map[string]bool{
+	"data":        true,
+	"information": true,
+	"metadata":    true,
+	"series":      true,
+	"species":     true,
+	"sheep":       true,
+	"fish":        true,
+	"news":        true,
+}
+
+// matchCase returns word with the capitalization of like
+func matchCase(like, word string) string {
+	switch {
+	case like == strings.ToUpper(like):
+		return strings.ToUpper(word)
+	case like == capitalize(like):
+		return capitalize(word)
+	}
+	return word
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) >= 0
+}
+
+// pluralize returns the English plural of a singular noun
+func pluralize(s string) string {
+	lower := strings.ToLower(s)
+	if lower == "" || uncountable[lower] {
+		return s
+	}
+	if plural, ok := irregularPlurals[lower]; ok {
+		return matchCase(s, plural)
+	}
+	if plural, ok := vesPlurals[lower]; ok {
+		return matchCase(s, plural)
+	}
+
+	var plural string
+	switch n := len(lower); {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		plural = lower + "es"
+	case n > 1 && lower[n-1] == 'y' && !isVowel(lower[n-2]):
+		plural = lower[:n-1] + "ies"
+	default:
+		plural = lower + "s"
+	}
+	return matchCase(s, plural)
+}
+
+// singularize returns the English singular of a plural noun
+func singularize(s string) string {
+	lower := strings.ToLower(s)
+	if lower == "" || uncountable[lower] {
+		return s
+	}
+	for _, plurals := range []map[string]string{irregularPlurals, vesPlurals} {
+		for singular, plural := range plurals {
+			if lower == plural {
+				return matchCase(s, singular)
+			}
+		}
+	}
+
+	var singular string
+	switch n := len(lower); {
+	case strings.HasSuffix(lower, "ies") && ieSingulars[lower[:n-1]]:
+		singular = lower[:n-1]
+	case strings.HasSuffix(lower, "ies") && n > 3:
+		singular = lower[:n-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		singular = lower[:n-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"):
+		singular = lower
+	case strings.HasSuffix(lower, "s"):
+		singular = lower[:n-1]
+	default:
+		singular = lower
+	}
+	return matchCase(s, singular)
+}
+
+// moduleBase returns the last element of a module path, skipping a major
+// version suffix: "github.com/user/mylib/v2" becomes "mylib"
+func moduleBase(module string) string {
+	base := path.Base(module)
+	if isMajorVersion(base) {
+		base = path.Base(path.Dir(module))
+	}
+	return base
+}
+
+// moduleParent returns everything before the last module path element:
+// "github.com/user/mylib" becomes "github.com/user"
+func moduleParent(module string) string {
+	if isMajorVersion(path.Base(module)) {
+		module = path.Dir(module)
+	}
+	return path.Dir(module)
+}
+
+// modulePath joins import path elements onto a module path:
+// modulePath "github.com/user/app" "internal/config" is
+// "github.com/user/app/internal/config"
+func modulePath(module string, elem ...string) string {
+	return path.Join(append([]string{module}, elem...)...)
+}
+
+// isMajorVersion reports whether elem is a module major version suffix like v2
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	for _, r := range elem[1:] {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return elem != "v0" && elem != "v1"
+}
@@ -0,0 +1,43 @@
+package generator
+
+import "testing"
+
+func TestInflection(t *testing.T) {
+	tests := []struct {
+		singular string
+		plural   string
+	}{
+		{"user", "users"},
+		{"category", "categories"},
+		{"key", "keys"},
+		{"address", "addresses"},
+		{"box", "boxes"},
+		{"match", "matches"},
+		{"child", "children"},
+		{"person", "people"},
+		{"data", "data"},
+		{"archive", "archives"},
+		{"drive", "drives"},
+		{"movie", "movies"},
+		{"cookie", "cookies"},
+		{"pie", "pies"},
+		{"chef", "chefs"},
+		{"roof", "roofs"},
+		{"belief", "beliefs"},
+		{"staff", "staffs"},
+		{"leaf", "leaves"},
+		{"knife", "knives"},
+		{"wolf", "wolves"},
+		{"Shelf", "Shelves"},
+		{"MOVIE", "MOVIES"},
+	}
+
+	for _, tt := range tests {
+		if got := pluralize(tt.singular); got != tt.plural {
+			t.Errorf("pluralize(%q) = %q, want %q", tt.singular, got, tt.plural)
+		}
+		if got := singularize(tt.plural); got != tt.singular {
+			t.Errorf("singularize(%q) = %q, want %q", tt.plural, got, tt.singular)
+		}
+	}
+}
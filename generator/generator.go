@@ -2,10 +2,9 @@ package generator
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-	"text/template"
 )
 
 // ProjectType represents different types of Go projects
@@ -18,227 +17,345 @@ const (
 	ProjectTypeLibrary ProjectType = "library"
 )
 
+// DefaultLicense is named in generated READMEs when no license is configured
+const DefaultLicense = "MIT"
+
 // ProjectConfig holds configuration for project generation
 type ProjectConfig struct {
-	Name        string
-	Module      string
-	Type        ProjectType
-	Description string
-	Author      string
-	GoVersion   string
-	OutputPath  string
+	Name        string         `json:"name"`
+	Module      string         `json:"module"`
+	Type        ProjectType    `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Author      string         `json:"author,omitempty"`
+	License     string         `json:"license,omitempty"`
+	GoVersion   string         `json:"go_version"`
+	OutputPath  string         `json:"output_path,omitempty"`
+	OnConflict  ConflictPolicy `json:"on_conflict"`
+	// GoCheck decides whether invalid rendered Go files abort generation
+	// (the default), are written with a warning, or are not checked
+	GoCheck GoCheckMode `json:"go_check,omitempty"`
+	// TemplateDir loads the project type from a local template directory
+	// instead of the built-in templates; Type is ignored when it is set
+	TemplateDir string `json:"template_dir,omitempty"`
+	// Vars are custom values available to templates as {{.Vars.Name}}
+	Vars map[string]string `json:"vars,omitempty"`
+	// OverrideDir holds files that replace or add entries of the project
+	// type, keyed by the same paths as ProjectStructure.Files
+	OverrideDir string `json:"override_dir,omitempty"`
+	// Exclude drops files and directories from the project type
+	Exclude []string `json:"exclude,omitempty"`
+	// Features are the add-ons layered on top of the project type; New
+	// expands them with the features they require
+	Features []string `json:"features,omitempty"`
+	// Hooks selects the built-in post-generation hooks run by RunHooks;
+	// empty means DefaultHooks, which only formats
+	Hooks []string `json:"hooks,omitempty"`
+	// Origins records where values came from (a flag, a config file, git
+	// config, ...), keyed by config file key such as "module" or "go_version"
+	Origins map[string]string `json:"-"`
 }
 
 // ProjectStructure defines the directory and file structure
 type ProjectStructure struct {
 	Directories []string
 	Files       map[string]string
+	// Partials are named templates available to every file; they are not
+	// written out themselves
+	Partials map[string]string
 }
 
 // Generator handles project structure generation
 type Generator struct {
 	config    ProjectConfig
 	structure ProjectStructure
+	prompter  ConflictPrompter
+	custom    *CustomTemplate
+	features  []Feature
+	// projectDir replaces OutputPath/Name as the project root when set
+	projectDir string
 }
 
 // NewGenerator creates a new Generator instance
-func NewGenerator(config ProjectConfig) *Generator {
-	if config.GoVersion == "" {
-		config.GoVersion = "1.24"
-	}
+func NewGenerator(config ProjectConfig) (*Generator, error) {
+	return New(config)
+}
 
-	g := &Generator{
-		config: config,
+// New creates a Generator for config, filling in defaults and validating
+// it. The project type comes from, in order of precedence, WithStructure,
+// WithTemplate, config.TemplateDir or config.Type; Features, WithFile,
+// WithPartial, OverrideDir and Exclude are then applied on top.
+func New(config ProjectConfig, opts ...Option) (*Generator, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
 	}
 
-	g.buildStructure()
-	return g
-}
+	if config.GoVersion == "" {
+		config.GoVersion = DefaultGoVersion
+	}
 
-// buildStructure builds the project structure based on project type
-func (g *Generator) buildStructure() {
-	switch g.config.Type {
-	case ProjectTypeAPI:
-		g.structure = g.buildAPIStructure()
-	case ProjectTypeCLI:
-		g.structure = g.buildCLIStructure()
-	case ProjectTypeMicro:
-		g.structure = g.buildMicroserviceStructure()
-	case ProjectTypeLibrary:
-		g.structure = g.buildLibraryStructure()
-	default:
-		g.structure = g.buildAPIStructure()
+	if config.OnConflict == "" {
+		config.OnConflict = ConflictFail
 	}
-}
 
-// Generate creates the project structure on disk
-func (g *Generator) Generate() error {
-	basePath := filepath.Join(g.config.OutputPath, g.config.Name)
+	if config.License == "" {
+		config.License = DefaultLicense
+	}
 
-	// Create base directory
-	if err := os.MkdirAll(basePath, 0755); err != nil {
-		return fmt.Errorf("failed to create base directory: %w", err)
+	if config.GoCheck == "" {
+		config.GoCheck = GoCheckError
 	}
 
-	// Create all directories
-	for _, dir := range g.structure.Directories {
-		dirPath := filepath.Join(basePath, dir)
-		if err := os.MkdirAll(dirPath, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	if o.structure != nil {
+		if o.typeName == "" {
+			return nil, fmt.Errorf("WithStructure needs a project type name")
 		}
+		config.Type = o.typeName
 	}
 
-	// Create all files
-	for filePath, content := range g.structure.Files {
-		fullPath := filepath.Join(basePath, filePath)
+	registered := o.structure == nil && o.template == nil && config.TemplateDir == ""
+	if err := config.validate(registered); err != nil {
+		return nil, err
+	}
 
-		// Parse template
-		tmpl, err := template.New(filePath).Parse(content)
-		if err != nil {
-			return fmt.Errorf("failed to parse template for %s: %w", filePath, err)
-		}
+	g := &Generator{
+		config:     config,
+		prompter:   o.prompter,
+		projectDir: o.projectDir,
+	}
 
-		// Create file
-		file, err := os.Create(fullPath)
+	switch {
+	case o.structure != nil:
+		g.structure = *o.structure
+	case o.template != nil:
+		if err := g.useTemplate(o.template); err != nil {
+			return nil, err
+		}
+	case config.TemplateDir != "":
+		custom, err := LoadTemplateDir(config.TemplateDir)
 		if err != nil {
-			return fmt.Errorf("failed to create file %s: %w", filePath, err)
+			return nil, err
+		}
+		if err := g.useTemplate(custom); err != nil {
+			return nil, err
+		}
+	default:
+		if err := g.useType(); err != nil {
+			return nil, err
 		}
-		defer file.Close()
+	}
+
+	if err := g.applyFeatures(); err != nil {
+		return nil, err
+	}
 
-		// Execute template
-		if err := tmpl.Execute(file, g.config); err != nil {
-			return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	if len(o.files) > 0 {
+		g.structure.Files = cloneTemplates(g.structure.Files)
+		for p, content := range o.files {
+			g.structure.Files[p] = content
+		}
+	}
+	if len(o.partials) > 0 {
+		g.structure.Partials = cloneTemplates(g.structure.Partials)
+		for name, content := range o.partials {
+			g.structure.Partials[name] = content
 		}
 	}
 
+	if err := g.applyOverrides(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// useType builds the structure of the registered type named by
+// config.Type and resolves the variables it declares
+func (g *Generator) useType() error {
+	def, ok := LookupType(string(g.config.Type))
+	if !ok {
+		return fmt.Errorf("unknown project type '%s'", g.config.Type)
+	}
+
+	vars, err := resolveVars(string(def.Name), def.Variables, g.config.Vars)
+	if err != nil {
+		return err
+	}
+
+	g.config.Type = def.Name
+	g.config.Vars = vars
+	g.structure = def.Build(g.config)
 	return nil
 }
 
-// buildAPIStructure creates structure for REST API projects
-func (g *Generator) buildAPIStructure() ProjectStructure {
-	return ProjectStructure{
-		Directories: []string{
-			"cmd/api",
-			"internal/handler",
-			"internal/service",
-			"internal/repository",
-			"internal/model",
-			"internal/middleware",
-			"internal/config",
-			"pkg/response",
-			"pkg/validator",
-			"migrations",
-			"docs",
-			"scripts",
-		},
-		Files: map[string]string{
-			"go.mod":                        goModTemplate,
-			"README.md":                     readmeTemplate,
-			".gitignore":                    gitignoreTemplate,
-			"Makefile":                      makefileAPITemplate,
-			"cmd/api/main.go":               mainAPITemplate,
-			"internal/config/config.go":     configTemplate,
-			"internal/handler/handler.go":   handlerTemplate,
-			"internal/service/service.go":   serviceTemplate,
-			"internal/repository/repository.go": repositoryTemplate,
-			"internal/model/model.go":       modelTemplate,
-			"internal/middleware/middleware.go": middlewareTemplate,
-			"pkg/response/response.go":      responseTemplate,
-			"docs/API.md":                   apiDocsTemplate,
-		},
-	}
-}
-
-// buildCLIStructure creates structure for CLI tool projects
-func (g *Generator) buildCLIStructure() ProjectStructure {
-	return ProjectStructure{
-		Directories: []string{
-			"cmd",
-			"internal/command",
-			"internal/config",
-			"pkg/utils",
-			"docs",
-		},
-		Files: map[string]string{
-			"go.mod":                    goModTemplate,
-			"README.md":                 readmeTemplate,
-			".gitignore":                gitignoreTemplate,
-			"Makefile":                  makefileCLITemplate,
-			"cmd/main.go":               mainCLITemplate,
-			"internal/command/root.go":  cliRootTemplate,
-			"internal/config/config.go": configTemplate,
-		},
-	}
-}
-
-// buildMicroserviceStructure creates structure for microservice projects
-func (g *Generator) buildMicroserviceStructure() ProjectStructure {
-	return ProjectStructure{
-		Directories: []string{
-			"cmd/server",
-			"internal/handler",
-			"internal/service",
-			"internal/repository",
-			"internal/model",
-			"internal/middleware",
-			"internal/config",
-			"pkg/grpc",
-			"pkg/http",
-			"proto",
-			"migrations",
-			"deployments/docker",
-			"deployments/k8s",
-			"scripts",
-		},
-		Files: map[string]string{
-			"go.mod":                        goModTemplate,
-			"README.md":                     readmeTemplate,
-			".gitignore":                    gitignoreTemplate,
-			"Makefile":                      makefileMicroTemplate,
-			"Dockerfile":                    dockerfileTemplate,
-			"cmd/server/main.go":            mainMicroTemplate,
-			"internal/config/config.go":     configTemplate,
-			"internal/handler/handler.go":   handlerTemplate,
-			"internal/service/service.go":   serviceTemplate,
-			"internal/repository/repository.go": repositoryTemplate,
-			"internal/model/model.go":       modelTemplate,
-			"deployments/k8s/deployment.yaml":   k8sDeploymentTemplate,
-			"deployments/k8s/service.yaml":      k8sServiceTemplate,
-		},
-	}
-}
-
-// buildLibraryStructure creates structure for library projects
-func (g *Generator) buildLibraryStructure() ProjectStructure {
-	return ProjectStructure{
-		Directories: []string{
-			"internal",
-			"examples",
-			"docs",
-		},
-		Files: map[string]string{
-			"go.mod":          goModTemplate,
-			"README.md":       readmeTemplate,
-			".gitignore":      gitignoreTemplate,
-			"Makefile":        makefileLibTemplate,
-			"{{.Name}}.go":    libraryMainTemplate,
-			"examples/main.go": libraryExampleTemplate,
-			"docs/USAGE.md":   usageDocsTemplate,
-		},
+// useTemplate makes a custom template the project type and resolves the
+// variables its manifest declares
+func (g *Generator) useTemplate(custom *CustomTemplate) error {
+	vars, err := custom.resolveVars(g.config.Vars)
+	if err != nil {
+		return err
+	}
+
+	g.custom = custom
+	g.config.Type = ProjectType(custom.Manifest.Name)
+	if g.config.Origins == nil {
+		g.config.Origins = make(map[string]string)
 	}
+	g.config.Origins["type"] = custom.Dir
+	g.config.Vars = vars
+	g.structure = custom.Structure
+	return nil
+}
+
+// Config returns the configuration the generator resolved, including the
+// project type and variables loaded from a template directory
+func (g *Generator) Config() ProjectConfig {
+	return g.config
+}
+
+// SetConflictPrompter sets the callback used by the prompt conflict policy
+func (g *Generator) SetConflictPrompter(prompter ConflictPrompter) {
+	g.prompter = prompter
+}
+
+// Generate creates the project structure on disk. Everything is rendered
+// and written to a staging directory first, then moved into place, so a
+// failed run leaves the output directory untouched. Existing files are
+// handled according to the configured ConflictPolicy. A ManifestFile
+// describing the run is written alongside the project files.
+func (g *Generator) Generate() (*Result, error) {
+	return g.Write(NewDiskSink(g.basePath()))
+}
+
+// Write renders the project and writes it to sink, applying the conflict
+// policy to whatever the sink already holds
+func (g *Generator) Write(sink Sink) (*Result, error) {
+	// Render every file up front so template errors surface before writing
+	r, err := g.render()
+	if err != nil {
+		return nil, err
+	}
+
+	// The manifest records every rendered file, including ones the conflict
+	// policy leaves alone, and is always rewritten
+	manifest, err := g.manifest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decide what to do with files that already exist
+	files, result, err := g.resolveConflicts(sink, r.files)
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = problemStrings(r.warnings)
+	recordManifest(sink, manifest, result)
+
+	out := make([]File, 0, len(files)+1)
+	for _, f := range append(files, manifest) {
+		out = append(out, File{Path: f.path, Content: f.content})
+	}
+	if err := sink.WriteProject(r.directories, out); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Project is a fully rendered project held in memory
+type Project struct {
+	// BasePath is the directory Generate would write to
+	BasePath    string   `json:"base_path"`
+	Directories []string `json:"directories"`
+	// Files holds every file, the project manifest included, sorted by path
+	Files []File `json:"files"`
+	// Warnings lists invalid Go files rendered under GoCheckWarn
+	Warnings []string `json:"warnings"`
+}
+
+// Render executes every template without looking at or touching any
+// output, for callers that want the files themselves
+func (g *Generator) Render() (*Project, error) {
+	r, err := g.render()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := g.manifest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &Project{
+		BasePath:    g.basePath(),
+		Directories: append([]string{}, r.directories...),
+		Files:       make([]File, 0, len(r.files)+1),
+		Warnings:    problemStrings(r.warnings),
+	}
+	for _, f := range append(r.files, manifest) {
+		project.Files = append(project.Files, File{Path: f.path, Content: f.content})
+	}
+	sort.Slice(project.Files, func(i, j int) bool { return project.Files[i].Path < project.Files[j].Path })
+
+	return project, nil
+}
+
+// basePath returns the root directory of the generated project
+func (g *Generator) basePath() string {
+	if g.projectDir != "" {
+		return g.projectDir
+	}
+	return filepath.Join(g.config.OutputPath, g.config.Name)
 }
 
 // GetProjectInfo returns formatted project information
 func (g *Generator) GetProjectInfo() string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("Project: %s\n", g.config.Name))
-	sb.WriteString(fmt.Sprintf("Module: %s\n", g.config.Module))
-	sb.WriteString(fmt.Sprintf("Type: %s\n", g.config.Type))
-	sb.WriteString(fmt.Sprintf("Go Version: %s\n", g.config.GoVersion))
-	sb.WriteString(fmt.Sprintf("Output Path: %s\n", filepath.Join(g.config.OutputPath, g.config.Name)))
+	sb.WriteString(fmt.Sprintf("Project: %s%s\n", g.config.Name, g.origin("name")))
+	sb.WriteString(fmt.Sprintf("Module: %s%s\n", g.config.Module, g.origin("module")))
+	sb.WriteString(fmt.Sprintf("Type: %s%s\n", g.config.Type, g.origin("type")))
+	if g.custom != nil {
+		sb.WriteString(fmt.Sprintf("Template: %s\n", g.custom.Dir))
+	}
+	if g.config.Author != "" {
+		sb.WriteString(fmt.Sprintf("Author: %s%s\n", g.config.Author, g.origin("author")))
+	}
+	sb.WriteString(fmt.Sprintf("License: %s%s\n", g.config.License, g.origin("license")))
+	sb.WriteString(fmt.Sprintf("Go Version: %s%s\n", g.config.GoVersion, g.origin("go_version")))
+	sb.WriteString(fmt.Sprintf("Output Path: %s%s\n", g.basePath(), g.origin("output")))
+	sb.WriteString(fmt.Sprintf("On Conflict: %s%s\n", g.config.OnConflict, g.origin("on_conflict")))
+	if g.config.OverrideDir != "" {
+		sb.WriteString(fmt.Sprintf("Overrides: %s\n", g.config.OverrideDir))
+	}
+	if len(g.config.Features) > 0 {
+		sb.WriteString(fmt.Sprintf("Features: %s%s\n", strings.Join(g.config.Features, ", "), g.origin("features")))
+	}
+	if len(g.config.Exclude) > 0 {
+		sb.WriteString(fmt.Sprintf("Excluded: %s\n", strings.Join(g.config.Exclude, ", ")))
+	}
+	if len(g.config.Vars) > 0 {
+		names := make([]string, 0, len(g.config.Vars))
+		for name := range g.config.Vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sb.WriteString("Variables:\n")
+		for _, name := range names {
+			sb.WriteString(fmt.Sprintf("  %s = %s\n", name, g.config.Vars[name]))
+		}
+	}
 	sb.WriteString(fmt.Sprintf("\nDirectories: %d\n", len(g.structure.Directories)))
 	sb.WriteString(fmt.Sprintf("Files: %d\n", len(g.structure.Files)))
 
 	return sb.String()
 }
+
+// origin formats where a config value came from for GetProjectInfo
+func (g *Generator) origin(key string) string {
+	if o := g.config.Origins[key]; o != "" {
+		return fmt.Sprintf(" (from %s)", o)
+	}
+	return ""
+}
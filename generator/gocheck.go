@@ -0,0 +1,211 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoCheckMode controls what happens when a rendered .go file is not valid Go
+type GoCheckMode string
+
+const (
+	// GoCheckError aborts before anything is written
+	GoCheckError GoCheckMode = "error"
+	// GoCheckWarn writes the file unformatted and reports a warning
+	GoCheckWarn GoCheckMode = "warn"
+	// GoCheckOff writes rendered Go files as they are
+	GoCheckOff GoCheckMode = "off"
+)
+
+// GoCheckModes lists every supported mode in display order
+var GoCheckModes = []GoCheckMode{GoCheckError, GoCheckWarn, GoCheckOff}
+
+// ParseGoCheckMode converts a flag value into a GoCheckMode
+func ParseGoCheckMode(s string) (GoCheckMode, error) {
+	for _, m := range GoCheckModes {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+
+	names := make([]string, len(GoCheckModes))
+	for i, m := range GoCheckModes {
+		names[i] = string(m)
+	}
+	return "", fmt.Errorf("invalid go check mode '%s'. Must be one of: %s", s, strings.Join(names, ", "))
+}
+
+// SourceProblem is a syntax or import error in a rendered Go file
+type SourceProblem struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (p SourceProblem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", p.Path, p.Line, p.Column, p.Message)
+}
+
+// GoSourceError is returned when rendered Go files are invalid under the
+// error check mode
+type GoSourceError struct {
+	Problems []SourceProblem
+}
+
+func (e *GoSourceError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = "  " + p.String()
+	}
+	return fmt.Sprintf("templates produced invalid Go code (use -go-check to change this):\n%s", strings.Join(lines, "\n"))
+}
+
+// problemStrings formats problems for Result and Plan warnings
+func problemStrings(problems []SourceProblem) []string {
+	out := make([]string, len(problems))
+	for i, p := range problems {
+		out[i] = p.String()
+	}
+	return out
+}
+
+// checkGoSource parses a rendered Go file and returns it gofmt-formatted.
+// When the file is not valid Go the content is returned unchanged together
+// with every problem found.
+func checkGoSource(filePath, content string) (string, []SourceProblem) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, content, parser.ParseComments|parser.AllErrors)
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) {
+			list.RemoveMultiples()
+			problems := make([]SourceProblem, len(list))
+			for i, e := range list {
+				problems[i] = SourceProblem{filePath, e.Pos.Line, e.Pos.Column, e.Msg}
+			}
+			return content, problems
+		}
+		return content, []SourceProblem{{Path: filePath, Message: err.Error()}}
+	}
+
+	if problems := unusedImports(fset, file); len(problems) > 0 {
+		return content, problems
+	}
+
+	formatted, err := format.Source([]byte(content))
+	if err != nil {
+		return content, []SourceProblem{{Path: filePath, Message: err.Error()}}
+	}
+	return string(formatted), nil
+}
+
+// missingPackages reports imports of packages inside the project module
+// that no rendered file provides, such as a handler importing a response
+// package the project type does not generate
+func (g *Generator) missingPackages(files []renderedFile) []SourceProblem {
+	packages := make(map[string]bool)
+	for _, f := range files {
+		if strings.HasSuffix(f.path, ".go") {
+			packages[path.Dir(f.path)] = true
+		}
+	}
+
+	var problems []SourceProblem
+	for _, f := range files {
+		if !strings.HasSuffix(f.path, ".go") {
+			continue
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, f.path, f.content, parser.ImportsOnly)
+		if err != nil {
+			// Syntax errors are reported by checkGoSource
+			continue
+		}
+
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || !strings.HasPrefix(importPath, g.config.Module+"/") {
+				continue
+			}
+			dir := strings.TrimPrefix(importPath, g.config.Module+"/")
+			if packages[dir] {
+				continue
+			}
+
+			pos := fset.Position(spec.Pos())
+			problems = append(problems, SourceProblem{
+				Path:    pos.Filename,
+				Line:    pos.Line,
+				Column:  pos.Column,
+				Message: fmt.Sprintf("%q is not generated by this project type", importPath),
+			})
+		}
+	}
+	return problems
+}
+
+// unusedImports reports imports whose package name is never referenced.
+// Without type information the name is guessed from the import path, so
+// paths with no obvious package name are assumed to be used.
+func unusedImports(fset *token.FileSet, file *ast.File) []SourceProblem {
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	var problems []SourceProblem
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "" || name == "_" || name == "." || used[name] {
+			continue
+		}
+
+		pos := fset.Position(spec.Pos())
+		problems = append(problems, SourceProblem{
+			Path:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+			Message: fmt.Sprintf("%q imported and not used", importPath),
+		})
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems
+}
+
+// importName guesses the package name of an import path, returning "" when
+// the last element is not a plain identifier
+func importName(importPath string) string {
+	name := moduleBase(importPath)
+	if i := strings.Index(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	if !token.IsIdentifier(name) {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Built-in post-generation hooks
+const (
+	HookGofmt     = "gofmt"
+	HookGoimports = "goimports"
+	HookModTidy   = "tidy"
+	HookGitInit   = "git"
+)
+
+// BuiltinHooks lists every built-in hook in the order RunHooks runs them
+var BuiltinHooks = []string{HookGofmt, HookGoimports, HookModTidy, HookGitInit}
+
+// DefaultHooks are the built-in hooks run when ProjectConfig.Hooks is empty.
+// Only formatting runs by default; tidy reaches the network and git creates
+// a repository, so both have to be asked for.
+var DefaultHooks = []string{HookGofmt}
+
+// HookNone disables every built-in hook when used as the only entry in
+// ProjectConfig.Hooks
+const HookNone = "none"
+
+// TemplateHook is a custom command declared in a template manifest
+type TemplateHook struct {
+	Name string `yaml:"name"`
+	Run  string `yaml:"run"`
+}
+
+// HookStatus is the outcome of a single hook
+type HookStatus string
+
+const (
+	HookOK      HookStatus = "ok"
+	HookFailed  HookStatus = "failed"
+	HookSkipped HookStatus = "skipped"
+)
+
+// HookResult reports what a hook did
+type HookResult struct {
+	Name    string     `json:"name"`
+	Command string     `json:"command"`
+	Status  HookStatus `json:"status"`
+	// Output holds the combined output of failed commands, or the reason a
+	// hook was skipped
+	Output string `json:"output,omitempty"`
+}
+
+// ParseHooks checks a list of built-in hook names
+func ParseHooks(names []string) ([]string, error) {
+	if len(names) == 1 && names[0] == HookNone {
+		return names, nil
+	}
+	for _, name := range names {
+		known := false
+		for _, h := range BuiltinHooks {
+			known = known || h == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown hook '%s'. Must be one of: %s, or %s", name, strings.Join(BuiltinHooks, ", "), HookNone)
+		}
+	}
+	return names, nil
+}
+
+// RunHooks runs the post-generation pipeline inside the generated project:
+// the selected formatting and tidy hooks, the commands declared in the
+// template manifest, then git so the initial commit includes their output.
+// The project manifest is brought up to date with what the hooks changed
+// before git runs. A failing hook does not stop the ones after it. Hooks
+// need a directory, so nothing runs when the result has no BasePath.
+func (g *Generator) RunHooks(result *Result) []HookResult {
+	basePath := result.BasePath
+	if basePath == "" {
+		return nil
+	}
+
+	enabled := g.config.Hooks
+	if len(enabled) == 0 {
+		enabled = DefaultHooks
+	}
+	selected := func(name string) bool {
+		for _, h := range enabled {
+			if h == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Only touch Go files this run actually wrote
+	var goFiles []string
+	for _, list := range [][]string{result.Created, result.Overwritten, result.Merged} {
+		for _, p := range list {
+			if strings.HasSuffix(p, ".go") {
+				goFiles = append(goFiles, p)
+			}
+		}
+	}
+
+	var results []HookResult
+	if selected(HookGofmt) {
+		results = append(results, gofmtHook(basePath, goFiles))
+	}
+	if selected(HookGoimports) {
+		results = append(results, goimportsHook(basePath, goFiles))
+	}
+	if selected(HookModTidy) {
+		results = append(results, modTidyHook(basePath))
+	}
+
+	if g.custom != nil {
+		for _, hook := range g.custom.Manifest.Hooks {
+			results = append(results, g.customHook(basePath, hook))
+		}
+	}
+
+	if err := g.refreshManifest(basePath, result); err != nil {
+		results = append(results, HookResult{
+			Name:    "manifest",
+			Command: "update " + ManifestFile,
+			Status:  HookFailed,
+			Output:  err.Error(),
+		})
+	}
+
+	if selected(HookGitInit) {
+		results = append(results, gitInitHook(basePath))
+	}
+
+	return results
+}
+
+// gofmtHook formats Go files in-process with go/format
+func gofmtHook(basePath string, files []string) HookResult {
+	res := HookResult{Name: HookGofmt, Command: "gofmt -w", Status: HookOK}
+	if len(files) == 0 {
+		res.Status, res.Output = HookSkipped, "no Go files were written"
+		return res
+	}
+
+	var failures []string
+	for _, p := range files {
+		fullPath := filepath.Join(basePath, p)
+		src, err := os.ReadFile(fullPath)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", p, err))
+			continue
+		}
+		formatted, err := format.Source(src)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", p, err))
+			continue
+		}
+		if !bytes.Equal(src, formatted) {
+			if err := os.WriteFile(fullPath, formatted, 0644); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", p, err))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		res.Status, res.Output = HookFailed, strings.Join(failures, "\n")
+	}
+	return res
+}
+
+// goimportsHook fixes imports when goimports is installed
+func goimportsHook(basePath string, files []string) HookResult {
+	res := HookResult{Name: HookGoimports, Command: "goimports -w"}
+	if len(files) == 0 {
+		res.Status, res.Output = HookSkipped, "no Go files were written"
+		return res
+	}
+	if _, err := exec.LookPath("goimports"); err != nil {
+		res.Status, res.Output = HookSkipped, "goimports not found in PATH"
+		return res
+	}
+	return runHook(res, basePath, "goimports", append([]string{"-w"}, files...)...)
+}
+
+// modTidyHook runs go mod tidy when the project has a go.mod
+func modTidyHook(basePath string) HookResult {
+	res := HookResult{Name: HookModTidy, Command: "go mod tidy"}
+	if _, err := os.Stat(filepath.Join(basePath, "go.mod")); err != nil {
+		res.Status, res.Output = HookSkipped, "no go.mod in the project"
+		return res
+	}
+	return runHook(res, basePath, "go", "mod", "tidy")
+}
+
+// gitInitHook creates a repository with an initial commit, unless the
+// project already lives inside a git work tree
+func gitInitHook(basePath string) HookResult {
+	res := HookResult{Name: HookGitInit, Command: "git init && git add -A && git commit"}
+	if _, err := exec.LookPath("git"); err != nil {
+		res.Status, res.Output = HookSkipped, "git not found in PATH"
+		return res
+	}
+
+	check := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	check.Dir = basePath
+	if out, err := check.Output(); err == nil && strings.TrimSpace(string(out)) == "true" {
+		res.Status, res.Output = HookSkipped, "project is already inside a git repository"
+		return res
+	}
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "-A"},
+		{"commit", "--quiet", "-m", "Initial commit from go-projo"},
+	} {
+		if res = runHook(res, basePath, "git", args...); res.Status != HookOK {
+			return res
+		}
+	}
+	return res
+}
+
+// customHook runs a manifest command through the platform shell with the
+// project settings exported as PROJO_* environment variables
+func (g *Generator) customHook(basePath string, hook TemplateHook) HookResult {
+	name := hook.Name
+	if name == "" {
+		name = hook.Run
+	}
+	res := HookResult{Name: name, Command: hook.Run}
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	cmd := exec.Command(shell, flag, hook.Run)
+	cmd.Dir = basePath
+	cmd.Env = append(os.Environ(),
+		"PROJO_NAME="+g.config.Name,
+		"PROJO_MODULE="+g.config.Module,
+		"PROJO_TYPE="+string(g.config.Type),
+		"PROJO_GO_VERSION="+g.config.GoVersion,
+	)
+	return finishHook(res, cmd)
+}
+
+func runHook(res HookResult, dir, name string, args ...string) HookResult {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return finishHook(res, cmd)
+}
+
+func finishHook(res HookResult, cmd *exec.Cmd) HookResult {
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		res.Status = HookFailed
+		res.Output = strings.TrimSpace(string(out))
+		if res.Output == "" {
+			res.Output = err.Error()
+		}
+		return res
+	}
+	res.Status = HookOK
+	return res
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// unformattedStructure is a project whose Go file the gofmt hook rewrites
+var unformattedStructure = ProjectStructure{
+	Directories: []string{"cmd"},
+	Files: map[string]string{
+		"go.mod":      "module {{.Module}}\n\ngo {{.GoVersion}}\n",
+		"cmd/main.go": "package main\nimport \"fmt\"\nfunc main() {\nfmt.Println( \"{{.Name}}\" )\n}\n",
+	},
+}
+
+func TestDiffAfterHooks(t *testing.T) {
+	config := ProjectConfig{
+		Name:       "demo",
+		Module:     "example.com/demo",
+		OutputPath: t.TempDir(),
+		Hooks:      []string{HookGofmt},
+	}
+	g, err := New(config, WithStructure("demo", unformattedStructure))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	result, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, h := range g.RunHooks(result) {
+		if h.Status == HookFailed {
+			t.Fatalf("hook %s failed: %s", h.Name, h.Output)
+		}
+	}
+
+	mainPath := filepath.Join(result.BasePath, "cmd", "main.go")
+	formatted, err := os.ReadFile(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(formatted) == "package main\nimport \"fmt\"\nfunc main() {\nfmt.Println( \"demo\" )\n}\n" {
+		t.Fatalf("gofmt hook did not format cmd/main.go")
+	}
+
+	plan, err := g.Diff()
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	for _, file := range plan.Files {
+		if file.Path != ManifestFile && file.Status != FileUnchanged {
+			t.Errorf("Diff() reports %s as %s right after generation:\n%s", file.Path, file.Status, file.Diff)
+		}
+	}
+
+	m, err := ReadManifest(result.BasePath)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	for _, e := range m.Files {
+		if e.Path != "cmd/main.go" {
+			continue
+		}
+		base, ok, err := e.BaseContent()
+		if err != nil || !ok {
+			t.Fatalf("BaseContent() = %v, %v", ok, err)
+		}
+		if base != string(formatted) {
+			t.Errorf("manifest base of cmd/main.go = %q, want the formatted file %q", base, formatted)
+		}
+	}
+
+	upgrade, err := g.Upgrade(m, true)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if len(upgrade.Unchanged) != 2 {
+		t.Errorf("Upgrade() unchanged = %v, want go.mod and cmd/main.go", upgrade.Unchanged)
+	}
+}
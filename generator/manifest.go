@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ManifestFile is written to the root of every generated project
+const ManifestFile = ".projo.json"
+
+// manifestSchema is bumped whenever the manifest format changes incompatibly
+const manifestSchema = 1
+
+// Manifest records how a project was generated so later commands can
+// reproduce, compare or upgrade it
+type Manifest struct {
+	Schema      int    `json:"schema"`
+	ToolVersion string `json:"tool_version"`
+	// TemplateRevision is a checksum over every directory, file and partial
+	// template of the project type, overrides included
+	TemplateRevision string          `json:"template_revision"`
+	Project          ProjectConfig   `json:"project"`
+	Files            []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry is the checksum of one generated file as written, after
+// any post-generation hooks
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	// Rendered is the checksum of the template output when a hook changed
+	// the file afterwards; it is omitted when it equals SHA256
+	Rendered string `json:"rendered,omitempty"`
+	// Base is the written content, gzipped and base64-encoded, used as the
+	// common ancestor when upgrading
+	Base string `json:"base,omitempty"`
+}
+
+// rendered returns the checksum of the template output the entry was
+// written from
+func (e ManifestEntry) rendered() string {
+	if e.Rendered != "" {
+		return e.Rendered
+	}
+	return e.SHA256
+}
+
+// hookOnly reports whether the difference between the template output and
+// the file on disk is entirely the work of the hooks: the template output
+// is what the entry was written from and the file has not been edited
+// since
+func (e ManifestEntry) hookOnly(rendered, current string) bool {
+	return e.rendered() == checksum(rendered) && e.SHA256 == checksum(current)
+}
+
+// BaseContent decodes the rendered content recorded for the entry. It
+// reports false when the manifest does not hold it.
+func (e ManifestEntry) BaseContent() (string, bool, error) {
+	if e.Base == "" {
+		return "", false, nil
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(e.Base)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode base of %s: %w", e.Path, err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decompress base of %s: %w", e.Path, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decompress base of %s: %w", e.Path, err)
+	}
+	if checksum(string(data)) != e.SHA256 {
+		return "", false, fmt.Errorf("base of %s does not match its checksum", e.Path)
+	}
+	return string(data), true, nil
+}
+
+// encodeBase gzips and base64-encodes content for ManifestEntry.Base
+func encodeBase(content string) (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ReadManifest loads the manifest of the project in dir
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+	}
+	if m.Schema > manifestSchema {
+		return nil, fmt.Errorf("%s was written by a newer go-projo (%s); upgrade go-projo to use it", ManifestFile, m.ToolVersion)
+	}
+	return &m, nil
+}
+
+// manifest builds the manifest for a rendering. The output path is left
+// out so the project can be moved, and nothing time-dependent is recorded
+// so regenerating an unchanged project leaves the manifest unchanged.
+func (g *Generator) manifest(r *rendering) (renderedFile, error) {
+	entries := make([]ManifestEntry, 0, len(r.files))
+	for _, f := range r.files {
+		entry, err := newManifestEntry(f.path, f.content)
+		if err != nil {
+			return renderedFile{}, err
+		}
+		entries = append(entries, entry)
+	}
+	return g.encodeManifest(entries)
+}
+
+// newManifestEntry records content written for path as rendered
+func newManifestEntry(path, content string) (ManifestEntry, error) {
+	base, err := encodeBase(content)
+	if err != nil {
+		return ManifestEntry{}, fmt.Errorf("failed to encode %s for the project manifest: %w", path, err)
+	}
+	return ManifestEntry{Path: path, SHA256: checksum(content), Base: base}, nil
+}
+
+// encodeManifest formats the manifest of the project with the given entries
+func (g *Generator) encodeManifest(entries []ManifestEntry) (renderedFile, error) {
+	m := Manifest{
+		Schema:           manifestSchema,
+		ToolVersion:      Version,
+		TemplateRevision: g.templateRevision(),
+		Project:          g.config,
+		Files:            entries,
+	}
+	m.Project.OutputPath = ""
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return renderedFile{}, fmt.Errorf("failed to encode project manifest: %w", err)
+	}
+	return renderedFile{path: ManifestFile, content: string(data) + "\n"}, nil
+}
+
+// recordManifest adds the manifest to the result list matching what
+// writing it does to the file on disk
+func recordManifest(sink Sink, manifest renderedFile, result *Result) {
+	existing, err := sink.ReadFile(manifest.path)
+	switch {
+	case err != nil:
+		result.Created = append(result.Created, manifest.path)
+	case string(existing) == manifest.content:
+		result.Unchanged = append(result.Unchanged, manifest.path)
+	default:
+		result.Overwritten = append(result.Overwritten, manifest.path)
+	}
+}
+
+// refreshManifest records the current content of the files this run wrote
+// in the manifest on disk, so the checksums and upgrade bases match what
+// the hooks left behind rather than the raw template output
+func (g *Generator) refreshManifest(basePath string, result *Result) error {
+	m, err := ReadManifest(basePath)
+	if err != nil {
+		return err
+	}
+
+	written := make(map[string]bool)
+	for _, list := range [][]string{result.Created, result.Overwritten, result.Merged} {
+		for _, p := range list {
+			written[p] = true
+		}
+	}
+
+	changed := false
+	for i, e := range m.Files {
+		if !written[e.Path] {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(basePath, e.Path))
+		if err != nil {
+			// Removed by a hook; the next diff reports it as missing
+			continue
+		}
+		if checksum(string(data)) == e.SHA256 {
+			continue
+		}
+		entry, err := newManifestEntry(e.Path, string(data))
+		if err != nil {
+			return err
+		}
+		if rendered := e.rendered(); rendered != entry.SHA256 {
+			entry.Rendered = rendered
+		}
+		m.Files[i] = entry
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	manifest, err := g.encodeManifest(m.Files)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(basePath, manifest.path), []byte(manifest.content), 0644); err != nil {
+		return fmt.Errorf("failed to update project manifest: %w", err)
+	}
+	return nil
+}
+
+// templateRevision hashes the project structure in a stable order
+func (g *Generator) templateRevision() string {
+	h := sha256.New()
+
+	dirs := append([]string(nil), g.structure.Directories...)
+	sort.Strings(dirs)
+	for _, d := range dirs {
+		fmt.Fprintf(h, "dir %s\n", d)
+	}
+
+	for _, set := range []struct {
+		kind  string
+		files map[string]string
+	}{
+		{"file", g.structure.Files},
+		{"partial", g.structure.Partials},
+	} {
+		names := make([]string, 0, len(set.files))
+		for name := range set.files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(h, "%s %s %d\n%s", set.kind, name, len(set.files[name]), set.files[name])
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checksum returns the hex sha256 of content
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
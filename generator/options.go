@@ -0,0 +1,69 @@
+package generator
+
+// Option customizes a Generator created with New
+type Option func(*options)
+
+// options collects everything the Option values set
+type options struct {
+	prompter   ConflictPrompter
+	projectDir string
+	template   *CustomTemplate
+	typeName   ProjectType
+	structure  *ProjectStructure
+	files      map[string]string
+	partials   map[string]string
+}
+
+// WithConflictPrompter sets the callback used by the prompt conflict policy
+func WithConflictPrompter(prompter ConflictPrompter) Option {
+	return func(o *options) {
+		o.prompter = prompter
+	}
+}
+
+// WithProjectDir renders into dir instead of OutputPath/Name, for projects
+// whose directory name differs from the project name
+func WithProjectDir(dir string) Option {
+	return func(o *options) {
+		o.projectDir = dir
+	}
+}
+
+// WithTemplate uses a template loaded with LoadTemplateDir or
+// LoadTemplateFS as the project type, instead of Type or TemplateDir
+func WithTemplate(t *CustomTemplate) Option {
+	return func(o *options) {
+		o.template = t
+	}
+}
+
+// WithStructure defines the project type in code: Type is set to name and
+// the structure is rendered like a built-in type
+func WithStructure(name ProjectType, structure ProjectStructure) Option {
+	return func(o *options) {
+		o.typeName = name
+		o.structure = &structure
+	}
+}
+
+// WithFile adds a file template to the project type, or replaces the one
+// with the same path
+func WithFile(path, content string) Option {
+	return func(o *options) {
+		if o.files == nil {
+			o.files = make(map[string]string)
+		}
+		o.files[path] = content
+	}
+}
+
+// WithPartial adds a named template every file template can include with
+// {{template "name" .}}
+func WithPartial(name, content string) Option {
+	return func(o *options) {
+		if o.partials == nil {
+			o.partials = make(map[string]string)
+		}
+		o.partials[name] = content
+	}
+}
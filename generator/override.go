@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// applyOverrides layers the override directory on top of the structure and
+// then drops every excluded entry
+func (g *Generator) applyOverrides() error {
+	if g.config.OverrideDir != "" {
+		if _, err := os.Stat(g.config.OverrideDir); err != nil {
+			return fmt.Errorf("invalid override directory: %w", err)
+		}
+
+		registry := NewTemplateRegistry(os.DirFS(g.config.OverrideDir))
+
+		files, err := registry.Files(".")
+		if err != nil {
+			return err
+		}
+
+		partials, err := registry.Partials(".")
+		if err != nil {
+			return err
+		}
+
+		g.structure.Files = cloneTemplates(g.structure.Files)
+		for p, content := range files {
+			g.structure.Files[p] = content
+		}
+
+		g.structure.Partials = cloneTemplates(g.structure.Partials)
+		for name, content := range partials {
+			g.structure.Partials[path.Join("overrides", name)] = content
+		}
+	}
+
+	if len(g.config.Exclude) == 0 {
+		return nil
+	}
+
+	for _, pattern := range g.config.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern '%s': %w", pattern, err)
+		}
+	}
+
+	files := make(map[string]string, len(g.structure.Files))
+	for p, content := range g.structure.Files {
+		if !g.isExcluded(p) {
+			files[p] = content
+		}
+	}
+	g.structure.Files = files
+
+	var dirs []string
+	for _, dir := range g.structure.Directories {
+		if !g.isExcluded(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	g.structure.Directories = dirs
+
+	return nil
+}
+
+// isExcluded reports whether p or one of its parent directories matches an
+// exclude entry, either exactly or as a path.Match pattern
+func (g *Generator) isExcluded(p string) bool {
+	return MatchPath(g.config.Exclude, p)
+}
+
+// MatchPath reports whether the slash-separated path p, or one of its
+// parent directories, matches any of the glob patterns
+func MatchPath(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+		for q := p; q != "." && q != "/"; q = path.Dir(q) {
+			if ok, _ := path.Match(pattern, q); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cloneTemplates copies a template map so built-in structures stay untouched
+func cloneTemplates(m map[string]string) map[string]string {
+	clone := make(map[string]string, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
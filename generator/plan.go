@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileStatus describes how a planned file relates to what is already on disk
+type FileStatus string
+
+const (
+	FileNew       FileStatus = "new"
+	FileChanged   FileStatus = "changed"
+	FileUnchanged FileStatus = "unchanged"
+)
+
+// PlannedFile is a single rendered file that Generate would write
+type PlannedFile struct {
+	Path    string     `json:"path"`
+	Size    int        `json:"size"`
+	Content string     `json:"content"`
+	Status  FileStatus `json:"status"`
+	// Diff is a unified diff against the existing file (or /dev/null for
+	// new files); it is empty when the file is unchanged
+	Diff string `json:"diff,omitempty"`
+}
+
+// Plan describes everything Generate would do, without touching disk
+type Plan struct {
+	BasePath    string        `json:"base_path"`
+	Directories []string      `json:"directories"`
+	Files       []PlannedFile `json:"files"`
+	// Warnings lists invalid Go files that would be written under GoCheckWarn
+	Warnings []string `json:"warnings"`
+}
+
+// Plan renders the project in memory and compares it with the target
+// directory. The project manifest is planned like any other file.
+func (g *Generator) Plan() (*Plan, error) {
+	return g.plan(nil)
+}
+
+// Diff is Plan for a generated project: files that differ from the template
+// output only by what the hooks changed after generation, as recorded in
+// the project manifest, are reported unchanged
+func (g *Generator) Diff() (*Plan, error) {
+	m, err := ReadManifest(g.basePath())
+	if err != nil {
+		return nil, err
+	}
+	return g.plan(m)
+}
+
+// plan builds the Plan; m is the manifest of the project, if any
+func (g *Generator) plan(m *Manifest) (*Plan, error) {
+	recorded := make(map[string]ManifestEntry)
+	if m != nil {
+		for _, e := range m.Files {
+			recorded[e.Path] = e
+		}
+	}
+
+	r, err := g.render()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := g.manifest(r)
+	if err != nil {
+		return nil, err
+	}
+	files := append(append([]renderedFile(nil), r.files...), manifest)
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	plan := &Plan{
+		BasePath:    g.basePath(),
+		Directories: r.directories,
+		Files:       make([]PlannedFile, 0, len(files)),
+		Warnings:    problemStrings(r.warnings),
+	}
+
+	for _, file := range files {
+		planned := PlannedFile{
+			Path:    file.path,
+			Size:    len(file.content),
+			Content: file.content,
+			Status:  FileNew,
+		}
+
+		existing, err := os.ReadFile(filepath.Join(plan.BasePath, file.path))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			planned.Diff = UnifiedDiff("/dev/null", "b/"+file.path, "", file.content)
+		case err != nil:
+			return nil, fmt.Errorf("failed to read existing file %s: %w", file.path, err)
+		case string(existing) == file.content:
+			planned.Status = FileUnchanged
+		case recorded[file.path].hookOnly(file.content, string(existing)):
+			planned.Status = FileUnchanged
+		default:
+			planned.Status = FileChanged
+			planned.Diff = UnifiedDiff("a/"+file.path, "b/"+file.path, string(existing), file.content)
+		}
+
+		plan.Files = append(plan.Files, planned)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// templateExt is stripped from template file names to get the output path
+const templateExt = ".tmpl"
+
+// TemplateRegistry loads project templates from a filesystem. File paths
+// relative to a directory, minus the .tmpl extension, become the paths of the
+// generated files. Files whose name starts with an underscore are partials:
+// they are not written out, but the templates they define can be used from
+// any other file with {{template "name" .}}.
+type TemplateRegistry struct {
+	fsys fs.FS
+}
+
+// NewTemplateRegistry creates a registry reading templates from fsys
+func NewTemplateRegistry(fsys fs.FS) *TemplateRegistry {
+	return &TemplateRegistry{fsys: fsys}
+}
+
+// Files returns every non-partial template under dir keyed by output path
+func (r *TemplateRegistry) Files(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	err := r.walk(dir, func(rel, content string) {
+		if !isPartial(rel) {
+			files[strings.TrimSuffix(rel, templateExt)] = content
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Partials returns every partial under dir keyed by its path
+func (r *TemplateRegistry) Partials(dir string) (map[string]string, error) {
+	partials := make(map[string]string)
+
+	err := r.walk(dir, func(rel, content string) {
+		if isPartial(rel) {
+			partials[rel] = content
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return partials, nil
+}
+
+// File returns the template stored at name
+func (r *TemplateRegistry) File(name string) (string, error) {
+	data, err := fs.ReadFile(r.fsys, name+templateExt)
+	if err != nil {
+		return "", fmt.Errorf("failed to load template %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// walk calls fn with the path relative to dir and the content of each file
+func (r *TemplateRegistry) walk(dir string, fn func(rel, content string)) error {
+	return fs.WalkDir(r.fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := fs.ReadFile(r.fsys, p)
+		if err != nil {
+			return fmt.Errorf("failed to load template %s: %w", p, err)
+		}
+
+		// Strip dir as a whole path element so dotfiles keep their dot
+		// when walking "."
+		rel := p
+		if dir != "." {
+			rel = strings.TrimPrefix(p, dir+"/")
+		}
+		fn(rel, string(data))
+		return nil
+	})
+}
+
+func isPartial(p string) bool {
+	return strings.HasPrefix(path.Base(p), "_")
+}
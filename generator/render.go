@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// renderedFile is a project file with its path and template already executed
+type renderedFile struct {
+	path    string
+	content string
+}
+
+// rendering is the fully rendered project, ready to be written
+type rendering struct {
+	directories []string
+	files       []renderedFile
+	// warnings lists invalid Go files written anyway under GoCheckWarn
+	warnings []SourceProblem
+}
+
+// TemplateError reports a file, path or partial template that could not be
+// parsed or executed, or whose output is not a valid project path
+type TemplateError struct {
+	// Template is the key in ProjectStructure.Files, the directory or the
+	// partial name
+	Template string
+	Err      error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("template %s: %v", e.Template, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// newTemplate creates a template with the settings shared by file paths,
+// directory names and file contents
+func (g *Generator) newTemplate(name string) *template.Template {
+	return template.New(name).Funcs(templateFuncs())
+}
+
+// render executes every directory, path and file template, returning the
+// results sorted by path. Rendered Go files are syntax-checked and
+// gofmt-formatted according to the configured GoCheckMode.
+func (g *Generator) render() (*rendering, error) {
+	r := &rendering{}
+	data := g.templateData()
+
+	seenDirs := make(map[string]bool)
+	for _, dir := range g.structure.Directories {
+		rendered, err := g.renderPath(dir, data)
+		if err != nil {
+			return nil, err
+		}
+		if seenDirs[rendered] || g.isExcluded(rendered) {
+			continue
+		}
+		seenDirs[rendered] = true
+		r.directories = append(r.directories, rendered)
+	}
+	sort.Strings(r.directories)
+
+	keys := make([]string, 0, len(g.structure.Files))
+	for key := range g.structure.Files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []SourceProblem
+	sources := make(map[string]string, len(keys))
+	for _, key := range keys {
+		filePath, err := g.renderPath(key, data)
+		if err != nil {
+			return nil, err
+		}
+		if g.isExcluded(filePath) {
+			continue
+		}
+		if filePath == ManifestFile {
+			return nil, &TemplateError{key, fmt.Errorf("renders to %s, which is reserved for the project manifest", ManifestFile)}
+		}
+		if other, ok := sources[filePath]; ok {
+			return nil, &TemplateError{key, fmt.Errorf("renders to %s, like template %s", filePath, other)}
+		}
+		sources[filePath] = key
+
+		// Parse template along with the partials it may reference
+		tmpl, err := g.newTemplate(key).Parse(g.structure.Files[key])
+		if err != nil {
+			return nil, &TemplateError{key, fmt.Errorf("failed to parse: %w", err)}
+		}
+		for name, partial := range g.structure.Partials {
+			if _, err := tmpl.New(name).Parse(partial); err != nil {
+				return nil, &TemplateError{name, fmt.Errorf("failed to parse partial: %w", err)}
+			}
+		}
+
+		// Execute template
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, &TemplateError{key, fmt.Errorf("failed to render %s: %w", filePath, err)}
+		}
+
+		content := buf.String()
+		if strings.HasSuffix(filePath, ".go") && g.config.GoCheck != GoCheckOff {
+			var found []SourceProblem
+			content, found = checkGoSource(filePath, content)
+			problems = append(problems, found...)
+		}
+
+		r.files = append(r.files, renderedFile{path: filePath, content: content})
+	}
+
+	if g.config.GoCheck != GoCheckOff {
+		problems = append(problems, g.missingPackages(r.files)...)
+	}
+
+	if len(problems) > 0 {
+		if g.config.GoCheck != GoCheckWarn {
+			return nil, &GoSourceError{Problems: problems}
+		}
+		r.warnings = problems
+	}
+
+	sort.Slice(r.files, func(i, j int) bool { return r.files[i].path < r.files[j].path })
+
+	return r, nil
+}
+
+// renderPath executes a path template and checks that the result stays
+// inside the project directory
+func (g *Generator) renderPath(p string, data templateData) (string, error) {
+	rendered := p
+	if strings.Contains(p, "{{") {
+		tmpl, err := g.newTemplate(p).Parse(p)
+		if err != nil {
+			return "", &TemplateError{p, fmt.Errorf("failed to parse path: %w", err)}
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", &TemplateError{p, fmt.Errorf("failed to render path: %w", err)}
+		}
+		rendered = buf.String()
+	}
+
+	cleaned := path.Clean(strings.ReplaceAll(rendered, "\\", "/"))
+	if rendered == "" || cleaned == "." || path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", &TemplateError{p, fmt.Errorf("path renders to '%s', which is not inside the project", rendered)}
+	}
+
+	return cleaned, nil
+}
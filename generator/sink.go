@@ -0,0 +1,264 @@
+package generator
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File is a generated file handed to a Sink
+type File struct {
+	// Path is slash-separated and relative to the project root
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Sink is where Generate writes a project: a directory on disk, memory or
+// an archive
+type Sink interface {
+	// ReadFile returns the content the sink already holds for a project
+	// path, or an error wrapping fs.ErrNotExist. Conflict policies are
+	// applied against it.
+	ReadFile(name string) ([]byte, error)
+	// WriteProject stores every directory and file of the project at once
+	WriteProject(dirs []string, files []File) error
+}
+
+// DiskSink writes the project into a directory. Everything goes to a
+// staging directory next to it first and is then moved into place, so a
+// failed write leaves the directory untouched.
+type DiskSink struct {
+	Dir string
+}
+
+// NewDiskSink creates a sink writing the project into dir
+func NewDiskSink(dir string) *DiskSink {
+	return &DiskSink{Dir: dir}
+}
+
+// ReadFile reads a file of the existing project directory
+func (s *DiskSink) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.Dir, filepath.FromSlash(name)))
+}
+
+// WriteProject stages the project and moves it into place
+func (s *DiskSink) WriteProject(dirs []string, files []File) error {
+	r := &rendering{directories: dirs}
+	for _, f := range files {
+		r.files = append(r.files, renderedFile{path: f.Path, content: f.Content})
+	}
+
+	// Stage next to the target so the final move stays on one filesystem
+	parent := filepath.Dir(s.Dir)
+	createdParent, err := mkdirTracked(parent)
+	if err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	staging, err := stage(parent, filepath.Base(s.Dir), r)
+	if err == nil {
+		defer os.RemoveAll(staging)
+		err = commitStaging(staging, s.Dir, r)
+	}
+	if err != nil && createdParent != "" {
+		os.RemoveAll(createdParent)
+	}
+	return err
+}
+
+// sinkDir returns the directory sink writes the project to, or "" when it
+// does not write to a directory
+func sinkDir(sink Sink) string {
+	if s, ok := sink.(*DiskSink); ok {
+		return s.Dir
+	}
+	return ""
+}
+
+// MemorySink keeps the project in memory, for tests and previews. Writing
+// to the same sink twice behaves like regenerating into a directory.
+type MemorySink struct {
+	Dirs  []string
+	Files map[string]string
+}
+
+// NewMemorySink creates an empty in-memory sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{Files: make(map[string]string)}
+}
+
+// ReadFile returns a file written earlier
+func (s *MemorySink) ReadFile(name string) ([]byte, error) {
+	content, ok := s.Files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return []byte(content), nil
+}
+
+// WriteProject records the directories and files
+func (s *MemorySink) WriteProject(dirs []string, files []File) error {
+	seen := make(map[string]bool, len(s.Dirs))
+	for _, d := range s.Dirs {
+		seen[d] = true
+	}
+	for _, d := range dirs {
+		if !seen[d] {
+			seen[d] = true
+			s.Dirs = append(s.Dirs, d)
+		}
+	}
+	sort.Strings(s.Dirs)
+
+	for _, f := range files {
+		s.Files[f.Path] = f.Content
+	}
+	return nil
+}
+
+// ArchiveFormat selects the archive type written by ArchiveSink
+type ArchiveFormat string
+
+const (
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+	ArchiveZip   ArchiveFormat = "zip"
+)
+
+// ParseArchiveFormat converts a flag value into an ArchiveFormat
+func ParseArchiveFormat(s string) (ArchiveFormat, error) {
+	switch s {
+	case "tar.gz", "tgz":
+		return ArchiveTarGz, nil
+	case "zip":
+		return ArchiveZip, nil
+	}
+	return "", fmt.Errorf("invalid archive format '%s'. Must be one of: tar.gz, zip", s)
+}
+
+// ArchiveFormatFor picks the archive format from a file name extension
+func ArchiveFormatFor(name string) (ArchiveFormat, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveZip, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveTarGz, nil
+	}
+	return "", fmt.Errorf("cannot tell the archive format of %s; use a .tar.gz, .tgz or .zip name", name)
+}
+
+// ArchiveSink writes the project as an archive to a stream. Entries are
+// placed under a top-level directory named after the project root.
+type ArchiveSink struct {
+	w      io.Writer
+	format ArchiveFormat
+	root   string
+}
+
+// NewArchiveSink creates a sink writing an archive of the given format to
+// w, with every entry under root
+func NewArchiveSink(w io.Writer, format ArchiveFormat, root string) *ArchiveSink {
+	return &ArchiveSink{w: w, format: format, root: root}
+}
+
+// ReadFile always reports that nothing exists; archives start empty
+func (s *ArchiveSink) ReadFile(name string) ([]byte, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// WriteProject writes the complete archive
+func (s *ArchiveSink) WriteProject(dirs []string, files []File) error {
+	var err error
+	if s.format == ArchiveZip {
+		err = s.writeZip(dirs, files)
+	} else {
+		err = s.writeTarGz(dirs, files)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s archive: %w", s.format, err)
+	}
+	return nil
+}
+
+func (s *ArchiveSink) writeTarGz(dirs []string, files []File) error {
+	now := time.Now()
+	gz := gzip.NewWriter(s.w)
+	tw := tar.NewWriter(gz)
+
+	for _, d := range archiveDirs(dirs, files) {
+		hdr := &tar.Header{Typeflag: tar.TypeDir, Name: path.Join(s.root, d) + "/", Mode: 0755, ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+	}
+	for _, f := range files {
+		hdr := &tar.Header{Typeflag: tar.TypeReg, Name: path.Join(s.root, f.Path), Mode: 0644, Size: int64(len(f.Content)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(tw, f.Content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (s *ArchiveSink) writeZip(dirs []string, files []File) error {
+	now := time.Now()
+	zw := zip.NewWriter(s.w)
+
+	for _, d := range archiveDirs(dirs, files) {
+		hdr := &zip.FileHeader{Name: path.Join(s.root, d) + "/", Modified: now}
+		hdr.SetMode(fs.ModeDir | 0755)
+		if _, err := zw.CreateHeader(hdr); err != nil {
+			return err
+		}
+	}
+	for _, f := range files {
+		hdr := &zip.FileHeader{Name: path.Join(s.root, f.Path), Method: zip.Deflate, Modified: now}
+		hdr.SetMode(0644)
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, f.Content); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// archiveDirs lists the root, every project directory and every parent of
+// a file, sorted so parents come before their children
+func archiveDirs(dirs []string, files []File) []string {
+	seen := map[string]bool{".": true}
+	all := []string{"."}
+	add := func(d string) {
+		for ; d != "." && !seen[d]; d = path.Dir(d) {
+			seen[d] = true
+			all = append(all, d)
+		}
+	}
+	for _, d := range dirs {
+		add(d)
+	}
+	for _, f := range files {
+		add(path.Dir(f.Path))
+	}
+	sort.Strings(all)
+	return all
+}
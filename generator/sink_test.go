@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+// archiveEntry is what the tests check of an archive member
+type archiveEntry struct {
+	mode    fs.FileMode
+	content string
+}
+
+func readTarGz(t *testing.T, data []byte) map[string]archiveEntry {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("not a gzip stream: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	entries := make(map[string]archiveEntry)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[hdr.Name] = archiveEntry{hdr.FileInfo().Mode(), string(content)}
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]archiveEntry {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("not a zip archive: %v", err)
+	}
+	entries := make(map[string]archiveEntry)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[f.Name] = archiveEntry{f.Mode(), string(content)}
+	}
+	return entries
+}
+
+func TestArchiveSink(t *testing.T) {
+	dirs := []string{"docs", "internal/config"}
+	files := []File{
+		{Path: "go.mod", Content: "module demo\n"},
+		{Path: "cmd/demo/main.go", Content: "package main\n"},
+	}
+	want := map[string]archiveEntry{
+		"demo/":                 {fs.ModeDir | 0755, ""},
+		"demo/cmd/":             {fs.ModeDir | 0755, ""},
+		"demo/cmd/demo/":        {fs.ModeDir | 0755, ""},
+		"demo/docs/":            {fs.ModeDir | 0755, ""},
+		"demo/internal/":        {fs.ModeDir | 0755, ""},
+		"demo/internal/config/": {fs.ModeDir | 0755, ""},
+		"demo/go.mod":           {0644, "module demo\n"},
+		"demo/cmd/demo/main.go": {0644, "package main\n"},
+	}
+
+	tests := []struct {
+		format ArchiveFormat
+		read   func(*testing.T, []byte) map[string]archiveEntry
+	}{
+		{ArchiveTarGz, readTarGz},
+		{ArchiveZip, readZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			sink := NewArchiveSink(&buf, tt.format, "demo")
+			if _, err := sink.ReadFile("go.mod"); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("ReadFile() error = %v, want fs.ErrNotExist", err)
+			}
+			if err := sink.WriteProject(dirs, files); err != nil {
+				t.Fatalf("WriteProject() error = %v", err)
+			}
+
+			got := tt.read(t, buf.Bytes())
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("archive entries = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestArchiveFormatFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ArchiveFormat
+		wantErr bool
+	}{
+		{"demo.zip", ArchiveZip, false},
+		{"demo.ZIP", ArchiveZip, false},
+		{"demo.tar.gz", ArchiveTarGz, false},
+		{"out/demo.tgz", ArchiveTarGz, false},
+		{"demo.tar", "", true},
+		{"demo", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ArchiveFormatFor(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ArchiveFormatFor(%q) = %q, %v, want %q (error %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestWriteMemorySink(t *testing.T) {
+	g := newTestGenerator(t, map[string]string{
+		"go.mod":      "module {{.Module}}\n",
+		"cmd/main.go": "package main\n",
+	})
+
+	sink := NewMemorySink()
+	result, err := g.Write(sink)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if result.BasePath != "" {
+		t.Errorf("BasePath = %q, want none for a memory sink", result.BasePath)
+	}
+	if hooks := g.RunHooks(result); hooks != nil {
+		t.Errorf("RunHooks() ran %v for a memory sink", hooks)
+	}
+	if got := sink.Files["go.mod"]; got != "module example.com/demo\n" {
+		t.Errorf("go.mod = %q", got)
+	}
+	if _, ok := sink.Files[ManifestFile]; !ok {
+		t.Errorf("the manifest was not written")
+	}
+
+	// Writing again behaves like regenerating into a directory
+	sink.Files["cmd/main.go"] = "package main // edited\n"
+	var conflict *ConflictError
+	if _, err := g.Write(sink); !errors.As(err, &conflict) || !reflect.DeepEqual(conflict.Paths, []string{"cmd/main.go"}) {
+		t.Errorf("second Write() error = %v, want a conflict on cmd/main.go", err)
+	}
+
+	delete(sink.Files, "cmd/main.go")
+	result, err = g.Write(sink)
+	if err != nil {
+		t.Fatalf("third Write() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Created, []string{"cmd/main.go"}) {
+		t.Errorf("Created = %v, want only the deleted file", result.Created)
+	}
+	if !reflect.DeepEqual(result.Unchanged, []string{"go.mod", ManifestFile}) {
+		t.Errorf("Unchanged = %v, want go.mod and the manifest", result.Unchanged)
+	}
+}
+
+func TestGenerateBasePath(t *testing.T) {
+	dir := t.TempDir()
+	g, err := New(ProjectConfig{Name: "demo", Module: "example.com/demo", OutputPath: t.TempDir()},
+		WithStructure("demo", ProjectStructure{Files: map[string]string{"go.mod": "module {{.Module}}\n"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := g.Write(NewDiskSink(dir))
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if result.BasePath != dir {
+		t.Errorf("BasePath = %q, want the sink directory %q", result.BasePath, dir)
+	}
+}
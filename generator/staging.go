@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stagingPrefix marks temporary directories created next to the target project
+const stagingPrefix = ".projo-staging-"
+
+// stage writes the rendered project into a fresh staging directory inside
+// parent and verifies it, returning the staging path
+func stage(parent, name string, r *rendering) (string, error) {
+	staging, err := mkdirStaging(parent, stagingPrefix+name+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	if err := writeTree(staging, r); err != nil {
+		os.RemoveAll(staging)
+		return "", err
+	}
+
+	if err := verifyTree(staging, r.files); err != nil {
+		os.RemoveAll(staging)
+		return "", err
+	}
+
+	return staging, nil
+}
+
+// mkdirStaging creates a uniquely named directory in parent. Unlike
+// os.MkdirTemp, which always uses mode 0700, it creates the directory with
+// 0755 less the umask, since a new project's staging directory becomes its
+// root.
+func mkdirStaging(parent, prefix string) (string, error) {
+	for i := 0; ; i++ {
+		dir := filepath.Join(parent, fmt.Sprintf("%s%d-%d", prefix, os.Getpid(), time.Now().UnixNano()))
+		err := os.Mkdir(dir, 0755)
+		if errors.Is(err, fs.ErrExist) && i < 100 {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return dir, nil
+	}
+}
+
+// writeTree creates every directory and file of the project under root
+func writeTree(root string, r *rendering) error {
+	for _, dir := range r.directories {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	for _, file := range r.files {
+		fullPath := filepath.Join(root, file.path)
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", file.path, err)
+		}
+
+		if err := os.WriteFile(fullPath, []byte(file.content), 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", file.path, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyTree checks that every staged file was written completely
+func verifyTree(root string, files []renderedFile) error {
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(root, file.path))
+		if err != nil {
+			return fmt.Errorf("failed to verify staged file %s: %w", file.path, err)
+		}
+		if string(data) != file.content {
+			return fmt.Errorf("staged file %s does not match rendered content", file.path)
+		}
+	}
+	return nil
+}
+
+// commitStaging moves a verified staging tree to basePath. A missing target
+// is replaced in a single rename; an existing one is updated file by file,
+// restoring the original files if any move fails.
+func commitStaging(staging, basePath string, r *rendering) error {
+	if _, err := os.Lstat(basePath); errors.Is(err, fs.ErrNotExist) {
+		if err := os.Rename(staging, basePath); err != nil {
+			return fmt.Errorf("failed to move project into place: %w", err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", basePath, err)
+	}
+
+	// Keep replaced files inside the staging directory so they can be restored
+	backup := filepath.Join(staging, stagingPrefix+"backup")
+	type move struct {
+		target   string
+		backedUp bool
+	}
+	var (
+		done    []move
+		created []string
+	)
+
+	rollback := func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			m := done[i]
+			os.Remove(m.target)
+			if m.backedUp {
+				rel, _ := filepath.Rel(basePath, m.target)
+				os.Rename(filepath.Join(backup, rel), m.target)
+			}
+		}
+		for i := len(created) - 1; i >= 0; i-- {
+			os.RemoveAll(created[i])
+		}
+	}
+
+	mkdir := func(dir string) error {
+		top, err := mkdirTracked(dir)
+		if top != "" {
+			created = append(created, top)
+		}
+		return err
+	}
+
+	for _, dir := range r.directories {
+		if err := mkdir(filepath.Join(basePath, dir)); err != nil {
+			rollback()
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	for _, file := range r.files {
+		target := filepath.Join(basePath, file.path)
+		m := move{target: target}
+
+		if err := mkdir(filepath.Dir(target)); err != nil {
+			rollback()
+			return fmt.Errorf("failed to create directory for %s: %w", file.path, err)
+		}
+
+		if _, err := os.Lstat(target); err == nil {
+			saved := filepath.Join(backup, file.path)
+			if err := os.MkdirAll(filepath.Dir(saved), 0755); err != nil {
+				rollback()
+				return fmt.Errorf("failed to back up %s: %w", file.path, err)
+			}
+			if err := os.Rename(target, saved); err != nil {
+				rollback()
+				return fmt.Errorf("failed to back up %s: %w", file.path, err)
+			}
+			m.backedUp = true
+		}
+
+		if err := os.Rename(filepath.Join(staging, file.path), target); err != nil {
+			if m.backedUp {
+				os.Rename(filepath.Join(backup, file.path), target)
+			}
+			rollback()
+			return fmt.Errorf("failed to move %s into place: %w", file.path, err)
+		}
+		done = append(done, m)
+	}
+
+	return nil
+}
+
+// mkdirTracked creates dir and any missing parents, returning the top-most
+// directory it had to create (empty if dir already existed) so callers can
+// remove exactly what they added
+func mkdirTracked(dir string) (string, error) {
+	top := ""
+	for p := filepath.Clean(dir); ; p = filepath.Dir(p) {
+		if _, err := os.Lstat(p); err == nil {
+			break
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		top = p
+		if parent := filepath.Dir(p); parent == p {
+			break
+		}
+	}
+
+	if top == "" {
+		return "", nil
+	}
+	return top, os.MkdirAll(dir, 0755)
+}
@@ -0,0 +1,194 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readTree returns every regular file under root keyed by slash path
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// assertNoStaging fails when a staging directory was left in dir
+func assertNoStaging(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), stagingPrefix) {
+			t.Errorf("staging directory %s was left behind", e.Name())
+		}
+	}
+}
+
+func TestCommitStagingNewDirectory(t *testing.T) {
+	parent := t.TempDir()
+	target := filepath.Join(parent, "demo")
+	r := &rendering{
+		directories: []string{"cmd", "docs"},
+		files: []renderedFile{
+			{path: "go.mod", content: "module demo\n"},
+			{path: "cmd/main.go", content: "package main\n"},
+		},
+	}
+
+	staging, err := stage(parent, "demo", r)
+	if err != nil {
+		t.Fatalf("stage() error = %v", err)
+	}
+	if err := commitStaging(staging, target, r); err != nil {
+		t.Fatalf("commitStaging() error = %v", err)
+	}
+
+	got := readTree(t, target)
+	want := map[string]string{"go.mod": "module demo\n", "cmd/main.go": "package main\n"}
+	if len(got) != len(want) {
+		t.Errorf("project files = %v, want %v", got, want)
+	}
+	for p, content := range want {
+		if got[p] != content {
+			t.Errorf("%s = %q, want %q", p, got[p], content)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(target, "docs")); err != nil || !info.IsDir() {
+		t.Errorf("empty directory docs was not created: %v", err)
+	}
+
+	// The root gets the mode of any other new directory, not os.MkdirTemp's 0700
+	reference := filepath.Join(parent, "reference")
+	if err := os.Mkdir(reference, 0755); err != nil {
+		t.Fatal(err)
+	}
+	rootInfo, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refInfo, err := os.Stat(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rootInfo.Mode().Perm() != refInfo.Mode().Perm() {
+		t.Errorf("project root mode = %v, want %v", rootInfo.Mode().Perm(), refInfo.Mode().Perm())
+	}
+	assertNoStaging(t, parent)
+}
+
+func TestCommitStagingRollback(t *testing.T) {
+	parent := t.TempDir()
+	target := filepath.Join(parent, "demo")
+	original := map[string]string{
+		"a.txt":         "old a\n",
+		"c.txt":         "old c\n",
+		"keep/mine.txt": "untouched\n",
+	}
+	for p, content := range original {
+		full := filepath.Join(target, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &rendering{
+		directories: []string{"added"},
+		files: []renderedFile{
+			{path: "a.txt", content: "new a\n"},
+			{path: "added/b.txt", content: "new b\n"},
+			{path: "c.txt", content: "new c\n"},
+			{path: "d.txt", content: "new d\n"},
+		},
+	}
+
+	staging, err := stage(parent, "demo", r)
+	if err != nil {
+		t.Fatalf("stage() error = %v", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// Make the move of c.txt fail after a.txt and added/b.txt are in place
+	if err := os.Remove(filepath.Join(staging, "c.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	err = commitStaging(staging, target, r)
+	if err == nil || !strings.Contains(err.Error(), "c.txt") {
+		t.Fatalf("commitStaging() error = %v, want a failure moving c.txt", err)
+	}
+
+	got := readTree(t, target)
+	if len(got) != len(original) {
+		t.Errorf("project files after rollback = %v, want %v", got, original)
+	}
+	for p, content := range original {
+		if got[p] != content {
+			t.Errorf("%s after rollback = %q, want %q", p, got[p], content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(target, "added")); !os.IsNotExist(err) {
+		t.Errorf("directory created by the failed commit was not removed: %v", err)
+	}
+}
+
+func TestStageRemovesStagingOnError(t *testing.T) {
+	parent := t.TempDir()
+
+	// A file cannot be written where a directory has to go
+	r := &rendering{
+		directories: []string{"docs"},
+		files:       []renderedFile{{path: "docs", content: "not a directory\n"}},
+	}
+	if _, err := stage(parent, "demo", r); err == nil {
+		t.Fatal("stage() succeeded writing a file over a directory")
+	}
+	assertNoStaging(t, parent)
+}
+
+func TestGenerateRenderErrorLeavesNothing(t *testing.T) {
+	parent := t.TempDir()
+	structure := ProjectStructure{
+		Files: map[string]string{
+			"go.mod":  "module {{.Module}}\n",
+			"bad.txt": "{{.Missing}}\n",
+		},
+	}
+	g, err := New(ProjectConfig{Name: "demo", Module: "example.com/demo", OutputPath: parent}, WithStructure("demo", structure))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := g.Generate(); err == nil {
+		t.Fatal("Generate() succeeded with a broken template")
+	}
+
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("Generate() left %s behind after a render error", e.Name())
+	}
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TypeDefinition registers a project type: how it is named on the command
+// line, how it is described in help and listings, and what it generates
+type TypeDefinition struct {
+	Name ProjectType
+	// Aliases are alternative names accepted wherever Name is
+	Aliases []string
+	// Description is a one-line summary shown in help and listings
+	Description string
+	// Build returns the directories, files and partials of the type
+	Build func(config ProjectConfig) ProjectStructure
+	// Variables are type-specific options, available to templates as
+	// {{.Vars.Name}} and set with -var name=value
+	Variables []TemplateVariable
+}
+
+// typeRegistry holds every registered project type in registration order
+var typeRegistry = struct {
+	sync.RWMutex
+	order   []ProjectType
+	types   map[ProjectType]*TypeDefinition
+	aliases map[string]ProjectType
+}{
+	types:   make(map[ProjectType]*TypeDefinition),
+	aliases: make(map[string]ProjectType),
+}
+
+// RegisterType adds a project type. Names and aliases must be unique
+// across all registered types.
+func RegisterType(def TypeDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("project type needs a name")
+	}
+	if def.Build == nil {
+		return fmt.Errorf("project type %s needs a Build function", def.Name)
+	}
+	for _, v := range def.Variables {
+		if v.Name == "" {
+			return fmt.Errorf("project type %s: every variable needs a name", def.Name)
+		}
+	}
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+
+	for _, name := range append([]string{string(def.Name)}, def.Aliases...) {
+		if _, ok := typeRegistry.types[ProjectType(name)]; ok {
+			return fmt.Errorf("project type name %s is already registered", name)
+		}
+		if _, ok := typeRegistry.aliases[name]; ok {
+			return fmt.Errorf("project type name %s is already registered", name)
+		}
+	}
+
+	def.Aliases = append([]string(nil), def.Aliases...)
+	typeRegistry.types[def.Name] = &def
+	typeRegistry.order = append(typeRegistry.order, def.Name)
+	for _, alias := range def.Aliases {
+		typeRegistry.aliases[alias] = def.Name
+	}
+	return nil
+}
+
+// MustRegisterType is RegisterType for use in init functions; it panics on
+// error
+func MustRegisterType(def TypeDefinition) {
+	if err := RegisterType(def); err != nil {
+		panic(err)
+	}
+}
+
+// LookupType finds a registered project type by name or alias
+func LookupType(name string) (TypeDefinition, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+
+	if canonical, ok := typeRegistry.aliases[name]; ok {
+		name = string(canonical)
+	}
+	def, ok := typeRegistry.types[ProjectType(name)]
+	if !ok {
+		return TypeDefinition{}, false
+	}
+	return *def, true
+}
+
+// ProjectTypes returns every registered project type in registration order
+func ProjectTypes() []TypeDefinition {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+
+	defs := make([]TypeDefinition, 0, len(typeRegistry.order))
+	for _, name := range typeRegistry.order {
+		defs = append(defs, *typeRegistry.types[name])
+	}
+	return defs
+}
+
+// TypeNames returns the names of every registered project type
+func TypeNames() []string {
+	var names []string
+	for _, def := range ProjectTypes() {
+		names = append(names, string(def.Name))
+	}
+	return names
+}
+
+// ParseProjectType converts a name or alias into a registered ProjectType
+func ParseProjectType(s string) (ProjectType, error) {
+	if def, ok := LookupType(s); ok {
+		return def.Name, nil
+	}
+	return "", fmt.Errorf("invalid project type '%s'. Must be one of: %s", s, strings.Join(TypeNames(), ", "))
+}
+
+// TypeHelp formats the registered types as an aligned two-column list for
+// help output, each line starting with indent
+func TypeHelp(indent string) string {
+	defs := ProjectTypes()
+
+	width := 0
+	for _, def := range defs {
+		width = max(width, len(def.Name))
+	}
+
+	var sb strings.Builder
+	for _, def := range defs {
+		desc := def.Description
+		if len(def.Aliases) > 0 {
+			aliases := append([]string(nil), def.Aliases...)
+			sort.Strings(aliases)
+			desc += fmt.Sprintf(" (alias: %s)", strings.Join(aliases, ", "))
+		}
+		fmt.Fprintf(&sb, "%s%-*s  %s\n", indent, width, def.Name, desc)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// restoreTypes puts the type registry back as it is now when the test ends
+func restoreTypes(t *testing.T) {
+	t.Helper()
+	typeRegistry.Lock()
+	order := append([]ProjectType(nil), typeRegistry.order...)
+	types := make(map[ProjectType]*TypeDefinition, len(typeRegistry.types))
+	for k, v := range typeRegistry.types {
+		types[k] = v
+	}
+	aliases := make(map[string]ProjectType, len(typeRegistry.aliases))
+	for k, v := range typeRegistry.aliases {
+		aliases[k] = v
+	}
+	typeRegistry.Unlock()
+
+	t.Cleanup(func() {
+		typeRegistry.Lock()
+		defer typeRegistry.Unlock()
+		typeRegistry.order, typeRegistry.types, typeRegistry.aliases = order, types, aliases
+	})
+}
+
+func buildEmpty(ProjectConfig) ProjectStructure {
+	return ProjectStructure{}
+}
+
+func TestRegisterType(t *testing.T) {
+	restoreTypes(t)
+
+	tests := []struct {
+		name    string
+		def     TypeDefinition
+		wantErr string
+	}{
+		{
+			name: "new type with aliases",
+			def:  TypeDefinition{Name: "worker", Aliases: []string{"job", "cron"}, Build: buildEmpty},
+		},
+		{
+			name:    "duplicate name",
+			def:     TypeDefinition{Name: "worker", Build: buildEmpty},
+			wantErr: "project type name worker is already registered",
+		},
+		{
+			name:    "name taken by an alias",
+			def:     TypeDefinition{Name: "job", Build: buildEmpty},
+			wantErr: "project type name job is already registered",
+		},
+		{
+			name:    "alias taken by a name",
+			def:     TypeDefinition{Name: "task", Aliases: []string{"api"}, Build: buildEmpty},
+			wantErr: "project type name api is already registered",
+		},
+		{
+			name:    "alias taken by an alias",
+			def:     TypeDefinition{Name: "task", Aliases: []string{"lib"}, Build: buildEmpty},
+			wantErr: "project type name lib is already registered",
+		},
+		{
+			name:    "no name",
+			def:     TypeDefinition{Build: buildEmpty},
+			wantErr: "needs a name",
+		},
+		{
+			name:    "no build function",
+			def:     TypeDefinition{Name: "task"},
+			wantErr: "needs a Build function",
+		},
+		{
+			name:    "unnamed variable",
+			def:     TypeDefinition{Name: "task", Build: buildEmpty, Variables: []TemplateVariable{{Default: "x"}}},
+			wantErr: "every variable needs a name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterType(tt.def)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("RegisterType() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RegisterType() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// A failed registration leaves nothing behind
+	if _, ok := LookupType("task"); ok {
+		t.Errorf("a rejected type was registered")
+	}
+	names := TypeNames()
+	if names[len(names)-1] != "worker" {
+		t.Errorf("TypeNames() = %v, want worker registered last", names)
+	}
+}
+
+func TestLookupType(t *testing.T) {
+	restoreTypes(t)
+	MustRegisterType(TypeDefinition{Name: "worker", Aliases: []string{"job"}, Build: buildEmpty})
+
+	tests := []struct {
+		name string
+		want ProjectType
+		ok   bool
+	}{
+		{"api", ProjectTypeAPI, true},
+		{"microservice", ProjectTypeMicro, true},
+		{"micro", ProjectTypeMicro, true},
+		{"lib", ProjectTypeLibrary, true},
+		{"worker", "worker", true},
+		{"job", "worker", true},
+		{"API", "", false},
+		{"", "", false},
+		{"webapp", "", false},
+	}
+
+	for _, tt := range tests {
+		def, ok := LookupType(tt.name)
+		if ok != tt.ok || def.Name != tt.want {
+			t.Errorf("LookupType(%q) = %q, %v, want %q, %v", tt.name, def.Name, ok, tt.want, tt.ok)
+		}
+
+		parsed, err := ParseProjectType(tt.name)
+		if (err == nil) != tt.ok || parsed != tt.want {
+			t.Errorf("ParseProjectType(%q) = %q, %v, want %q", tt.name, parsed, err, tt.want)
+		}
+	}
+}
+
+func TestMustRegisterTypePanics(t *testing.T) {
+	restoreTypes(t)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustRegisterType() did not panic on a duplicate name")
+		}
+	}()
+	MustRegisterType(TypeDefinition{Name: ProjectTypeAPI, Build: buildEmpty})
+}
@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// OpenProject reads the manifest of the project in dir and returns a
+// Generator that renders into dir with the recorded settings. A non-empty
+// templateDir replaces the recorded template directory, for templates that
+// have moved since the project was generated.
+func OpenProject(dir, templateDir string) (*Generator, *Manifest, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid project directory: %w", err)
+	}
+
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := m.Project
+	config.OutputPath = filepath.Dir(dir)
+	config.Origins = map[string]string{}
+	if templateDir != "" {
+		config.TemplateDir = templateDir
+	}
+
+	g, err := New(config, WithProjectDir(dir))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return g, m, nil
+}
+
+// UpgradeResult summarizes what Upgrade did with each file
+type UpgradeResult struct {
+	// BasePath is the project directory; it is empty for sinks that do not
+	// write to a directory
+	BasePath    string `json:"base_path,omitempty"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+	// TemplatesChanged is false when the templates are identical to the
+	// ones the project was generated from
+	TemplatesChanged bool `json:"templates_changed"`
+	// Added files are new in the templates
+	Added []string `json:"added"`
+	// Updated files were not modified since generation and were replaced
+	Updated []string `json:"updated"`
+	// Merged files combine local edits with template changes cleanly
+	Merged []string `json:"merged"`
+	// Conflicted files were merged with conflict markers to resolve by hand
+	Conflicted []string `json:"conflicted"`
+	// Kept files have local edits and no template changes
+	Kept []string `json:"kept"`
+	// Deleted files were removed locally and are not recreated
+	Deleted []string `json:"deleted"`
+	// Removed files are no longer produced by the templates; they are left
+	// in place for you to delete
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// Upgrade re-renders the project with the current templates and merges the
+// changes since the recorded manifest into the files on disk. Files nobody
+// edited are replaced, edited files get a three-way merge with the content
+// recorded in the manifest as the common ancestor, and files deleted
+// locally stay deleted. Unless dryRun is set the results and a refreshed
+// manifest are written through a DiskSink like Generate.
+func (g *Generator) Upgrade(m *Manifest, dryRun bool) (*UpgradeResult, error) {
+	return g.UpgradeSink(NewDiskSink(g.basePath()), m, dryRun)
+}
+
+// UpgradeSink is Upgrade for a project held by sink
+func (g *Generator) UpgradeSink(sink Sink, m *Manifest, dryRun bool) (*UpgradeResult, error) {
+	r, err := g.render()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpgradeResult{
+		BasePath:         sinkDir(sink),
+		FromVersion:      m.ToolVersion,
+		ToVersion:        Version,
+		TemplatesChanged: m.TemplateRevision != g.templateRevision(),
+		Added:            []string{},
+		Updated:          []string{},
+		Merged:           []string{},
+		Conflicted:       []string{},
+		Kept:             []string{},
+		Deleted:          []string{},
+		Removed:          []string{},
+		Unchanged:        []string{},
+	}
+
+	recorded := make(map[string]ManifestEntry, len(m.Files))
+	for _, e := range m.Files {
+		recorded[e.Path] = e
+	}
+
+	write := &rendering{directories: r.directories}
+	rendered := make(map[string]bool, len(r.files))
+	entries := make([]ManifestEntry, 0, len(r.files))
+	for _, file := range r.files {
+		rendered[file.path] = true
+
+		data, err := sink.ReadFile(file.path)
+		exists := err == nil
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read existing file %s: %w", file.path, err)
+		}
+		current := string(data)
+
+		entry, known := recorded[file.path]
+		// Files whose template output has not changed keep their recorded
+		// entry, so what the hooks did to them stays the upgrade base
+		if known && entry.rendered() == checksum(file.content) {
+			entries = append(entries, entry)
+		} else {
+			fresh, err := newManifestEntry(file.path, file.content)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, fresh)
+		}
+
+		switch {
+		case !known && !exists:
+			result.Added = append(result.Added, file.path)
+			write.files = append(write.files, file)
+		case known && !exists:
+			result.Deleted = append(result.Deleted, file.path)
+		case current == file.content, known && entry.hookOnly(file.content, current):
+			result.Unchanged = append(result.Unchanged, file.path)
+		case known && checksum(current) == entry.SHA256:
+			result.Updated = append(result.Updated, file.path)
+			write.files = append(write.files, file)
+		case known && checksum(file.content) == entry.rendered():
+			result.Kept = append(result.Kept, file.path)
+		default:
+			// Both sides changed: merge against the recorded base when the
+			// manifest has it, otherwise mark every difference
+			base, ok, err := entry.BaseContent()
+			if err != nil {
+				return nil, err
+			}
+			var merged string
+			var clean bool
+			if ok {
+				merged, clean = mergeThreeWay(base, current, file.content)
+			} else {
+				merged, clean = mergeTwoWay(current, file.content)
+			}
+			if clean {
+				result.Merged = append(result.Merged, file.path)
+			} else {
+				result.Conflicted = append(result.Conflicted, file.path)
+			}
+			write.files = append(write.files, renderedFile{path: file.path, content: merged})
+		}
+	}
+
+	for _, e := range m.Files {
+		if !rendered[e.Path] {
+			if _, err := sink.ReadFile(e.Path); err == nil {
+				result.Removed = append(result.Removed, e.Path)
+			}
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	manifest, err := g.encodeManifest(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]File, 0, len(write.files)+1)
+	for _, f := range append(write.files, manifest) {
+		out = append(out, File{Path: f.path, Content: f.content})
+	}
+	if err := sink.WriteProject(write.directories, out); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestGenerator creates a Generator for a small project with the given files
+func newTestGenerator(t *testing.T, files map[string]string) *Generator {
+	t.Helper()
+	g, err := New(ProjectConfig{
+		Name:       "demo",
+		Module:     "example.com/demo",
+		OutputPath: t.TempDir(),
+	}, WithStructure("demo", ProjectStructure{Files: files}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return g
+}
+
+// sinkManifest decodes the manifest held by sink
+func sinkManifest(t *testing.T, sink *MemorySink) *Manifest {
+	t.Helper()
+	var m Manifest
+	if err := json.Unmarshal([]byte(sink.Files[ManifestFile]), &m); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+	return &m
+}
+
+func TestUpgrade(t *testing.T) {
+	before := map[string]string{
+		"same.txt":     "same\n",
+		"kept.txt":     "kept\n",
+		"updated.txt":  "old\n",
+		"merged.txt":   "one\ntwo\nthree\n",
+		"conflict.txt": "one\ntwo\nthree\n",
+		"deleted.txt":  "deleted\n",
+		"removed.txt":  "removed\n",
+	}
+	after := map[string]string{
+		"same.txt":     "same\n",
+		"kept.txt":     "kept\n",
+		"updated.txt":  "new\n",
+		"merged.txt":   "one\ntwo\nTHREE\n",
+		"conflict.txt": "one\nTWO\nthree\n",
+		"deleted.txt":  "deleted again\n",
+		"added.txt":    "added\n",
+	}
+
+	sink := NewMemorySink()
+	if _, err := newTestGenerator(t, before).Write(sink); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	m := sinkManifest(t, sink)
+
+	// Local edits made after generation
+	sink.Files["kept.txt"] = "kept, edited\n"
+	sink.Files["merged.txt"] = "ONE\ntwo\nthree\n"
+	sink.Files["conflict.txt"] = "one\nzwei\nthree\n"
+	delete(sink.Files, "deleted.txt")
+
+	g := newTestGenerator(t, after)
+	dry, err := g.UpgradeSink(sink, m, true)
+	if err != nil {
+		t.Fatalf("UpgradeSink(dry run) error = %v", err)
+	}
+	if sink.Files["updated.txt"] != "old\n" {
+		t.Errorf("dry run wrote updated.txt")
+	}
+
+	result, err := g.UpgradeSink(sink, m, false)
+	if err != nil {
+		t.Fatalf("UpgradeSink() error = %v", err)
+	}
+	if !reflect.DeepEqual(dry, result) {
+		t.Errorf("dry run result = %+v, want %+v", dry, result)
+	}
+	if !result.TemplatesChanged {
+		t.Errorf("TemplatesChanged = false, want true")
+	}
+
+	lists := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"Unchanged", result.Unchanged, []string{"same.txt"}},
+		{"Kept", result.Kept, []string{"kept.txt"}},
+		{"Updated", result.Updated, []string{"updated.txt"}},
+		{"Merged", result.Merged, []string{"merged.txt"}},
+		{"Conflicted", result.Conflicted, []string{"conflict.txt"}},
+		{"Added", result.Added, []string{"added.txt"}},
+		{"Deleted", result.Deleted, []string{"deleted.txt"}},
+		{"Removed", result.Removed, []string{"removed.txt"}},
+	}
+	for _, l := range lists {
+		if !reflect.DeepEqual(l.got, l.want) {
+			t.Errorf("%s = %v, want %v", l.name, l.got, l.want)
+		}
+	}
+
+	contents := map[string]string{
+		"same.txt":     "same\n",
+		"kept.txt":     "kept, edited\n",
+		"updated.txt":  "new\n",
+		"merged.txt":   "ONE\ntwo\nTHREE\n",
+		"conflict.txt": "one\n" + markerExisting + "zwei\n" + markerSplit + "TWO\n" + markerNew + "three\n",
+		"added.txt":    "added\n",
+		"removed.txt":  "removed\n",
+	}
+	for p, want := range contents {
+		if got := sink.Files[p]; got != want {
+			t.Errorf("%s = %q, want %q", p, got, want)
+		}
+	}
+	if _, ok := sink.Files["deleted.txt"]; ok {
+		t.Errorf("deleted.txt was recreated")
+	}
+
+	// The refreshed manifest records the new template output as the base
+	refreshed := sinkManifest(t, sink)
+	if refreshed.TemplateRevision != g.templateRevision() {
+		t.Errorf("manifest template revision was not updated")
+	}
+	for _, e := range refreshed.Files {
+		base, ok, err := e.BaseContent()
+		if err != nil || !ok {
+			t.Errorf("BaseContent(%s) = %v, %v", e.Path, ok, err)
+			continue
+		}
+		if base != after[e.Path] {
+			t.Errorf("manifest base of %s = %q, want %q", e.Path, base, after[e.Path])
+		}
+	}
+}
+
+func TestManifestBaseContent(t *testing.T) {
+	content := "package main\n\nfunc main() {}\n"
+	entry, err := newManifestEntry("main.go", content)
+	if err != nil {
+		t.Fatalf("newManifestEntry() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		entry   ManifestEntry
+		want    string
+		ok      bool
+		wantErr string
+	}{
+		{
+			name:  "round trip",
+			entry: entry,
+			want:  content,
+			ok:    true,
+		},
+		{
+			name:  "no base",
+			entry: ManifestEntry{Path: "main.go", SHA256: entry.SHA256},
+		},
+		{
+			name:    "not base64",
+			entry:   ManifestEntry{Path: "main.go", SHA256: entry.SHA256, Base: "!!!"},
+			wantErr: "failed to decode",
+		},
+		{
+			name:    "not gzip",
+			entry:   ManifestEntry{Path: "main.go", SHA256: entry.SHA256, Base: "aGVsbG8="},
+			wantErr: "failed to decompress",
+		},
+		{
+			name:    "checksum mismatch",
+			entry:   ManifestEntry{Path: "main.go", SHA256: checksum("other"), Base: entry.Base},
+			wantErr: "does not match its checksum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := tt.entry.BaseContent()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("BaseContent() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BaseContent() error = %v", err)
+			}
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("BaseContent() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
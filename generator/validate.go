@@ -0,0 +1,228 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldError describes a single invalid ProjectConfig field
+type FieldError struct {
+	Field   string
+	Value   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %q: %s", e.Field, e.Value, e.Message)
+}
+
+// ValidationError lists every problem found in a ProjectConfig
+type ValidationError struct {
+	Problems []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("invalid project configuration:")
+	for _, p := range e.Problems {
+		sb.WriteString("\n  - ")
+		sb.WriteString(p.Error())
+	}
+	return sb.String()
+}
+
+// Validate checks every field that ends up in generated files or paths and
+// returns a *ValidationError listing all problems at once
+func (c ProjectConfig) Validate() error {
+	return c.validate(c.TemplateDir == "")
+}
+
+// validate is Validate, checking Type against the registered project types
+// only when checkType is set
+func (c ProjectConfig) validate(checkType bool) error {
+	var problems []FieldError
+	check := func(field, value string, err error) {
+		if err != nil {
+			problems = append(problems, FieldError{Field: field, Value: value, Message: err.Error()})
+		}
+	}
+
+	check("name", c.Name, ValidateName(c.Name))
+	check("module", c.Module, ValidateModulePath(c.Module))
+	check("go version", c.GoVersion, ValidateGoVersion(c.GoVersion))
+
+	if checkType {
+		if _, ok := LookupType(string(c.Type)); !ok {
+			check("type", string(c.Type), fmt.Errorf("unknown project type; must be one of: %s", strings.Join(TypeNames(), ", ")))
+		}
+	}
+
+	if c.GoCheck != "" {
+		if _, err := ParseGoCheckMode(string(c.GoCheck)); err != nil {
+			check("go-check", string(c.GoCheck), fmt.Errorf("unknown go check mode"))
+		}
+	}
+
+	if c.OnConflict != "" {
+		if _, err := ParseConflictPolicy(string(c.OnConflict)); err != nil {
+			check("on-conflict", string(c.OnConflict), fmt.Errorf("unknown conflict policy"))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// windowsReserved are file names that cannot be used on Windows, with or
+// without an extension
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+func isWindowsReserved(elem string) bool {
+	base, _, _ := strings.Cut(elem, ".")
+	return windowsReserved[strings.ToUpper(base)]
+}
+
+// ValidateName checks that name works both as the project directory and as
+// the source of the Go package name
+func ValidateName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("a project name is required")
+	case name == "." || name == "..":
+		return fmt.Errorf("cannot be . or ..")
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("is used as a directory name and cannot contain slashes; use -output to choose the parent directory")
+	case strings.IndexFunc(name, unicode.IsSpace) >= 0:
+		return fmt.Errorf("cannot contain spaces; try %q", toKebab(name))
+	case strings.ContainsAny(name, `<>:"|?*`):
+		return fmt.Errorf(`cannot contain any of < > : " | ? *`)
+	case strings.HasPrefix(name, "-"), strings.HasPrefix(name, "."):
+		return fmt.Errorf("cannot start with '-' or '.'")
+	case isWindowsReserved(name):
+		return fmt.Errorf("is a reserved file name on Windows")
+	}
+
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("cannot contain control characters")
+		}
+	}
+
+	// The package name drops everything but letters and digits
+	var first rune
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			first = r
+			break
+		}
+	}
+	switch {
+	case first == 0:
+		return fmt.Errorf("must contain at least one letter to form a Go package name")
+	case !unicode.IsLetter(first):
+		return fmt.Errorf("must start with a letter to form a Go package name (got package %q)", toPackageName(name))
+	}
+
+	return nil
+}
+
+// ValidateModulePath checks a module path against the rules the go command
+// applies to import paths
+func ValidateModulePath(module string) error {
+	if module == "" {
+		return fmt.Errorf("a module path is required, e.g. github.com/user/project")
+	}
+	if !utf8.ValidString(module) {
+		return fmt.Errorf("is not valid UTF-8")
+	}
+	if strings.HasPrefix(module, "/") || strings.HasSuffix(module, "/") {
+		return fmt.Errorf("cannot start or end with a slash")
+	}
+	if strings.Contains(module, "//") {
+		return fmt.Errorf("cannot contain empty path elements (//)")
+	}
+
+	elems := strings.Split(module, "/")
+	for _, elem := range elems {
+		if err := checkModuleElem(elem); err != nil {
+			return fmt.Errorf("invalid path element %q: %v", elem, err)
+		}
+	}
+
+	first := elems[0]
+	if strings.Contains(first, ".") {
+		for _, r := range first {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+				return fmt.Errorf("the host %q may only contain lower-case letters, digits, dots and hyphens", first)
+			}
+		}
+		if strings.HasPrefix(first, "-") {
+			return fmt.Errorf("the host %q cannot start with a hyphen", first)
+		}
+	}
+
+	if len(elems) > 1 {
+		last := elems[len(elems)-1]
+		if last == "v0" || last == "v1" {
+			return fmt.Errorf("major version suffix /%s is not allowed; only /v2 and later are used", last)
+		}
+	}
+
+	return nil
+}
+
+// checkModuleElem validates one slash-separated element of a module path
+func checkModuleElem(elem string) error {
+	if elem == "." || elem == ".." {
+		return fmt.Errorf("cannot be . or ..")
+	}
+	for _, r := range elem {
+		ok := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			r == '-' || r == '.' || r == '_' || r == '~'
+		if !ok {
+			if r == ' ' {
+				return fmt.Errorf("cannot contain spaces")
+			}
+			return fmt.Errorf("invalid character %q; only letters, digits and - . _ ~ are allowed", r)
+		}
+	}
+	if strings.HasSuffix(elem, ".") {
+		return fmt.Errorf("cannot end with a dot")
+	}
+	if strings.HasPrefix(elem, ".") {
+		return fmt.Errorf("cannot start with a dot")
+	}
+	if isWindowsReserved(elem) {
+		return fmt.Errorf("is a reserved file name on Windows")
+	}
+	return nil
+}
+
+// goVersionPattern matches go directive versions such as 1.24, 1.24.1 and
+// 1.25rc1
+var goVersionPattern = regexp.MustCompile(`^[1-9][0-9]*\.(0|[1-9][0-9]*)(\.(0|[1-9][0-9]*)|(rc|beta)[1-9][0-9]*)?$`)
+
+// ValidateGoVersion checks that v can be used in a go.mod go directive
+func ValidateGoVersion(v string) error {
+	if v == "" {
+		return fmt.Errorf("a Go version is required")
+	}
+	if strings.HasPrefix(v, "go") {
+		return fmt.Errorf("drop the 'go' prefix; use %q", strings.TrimPrefix(v, "go"))
+	}
+	if !goVersionPattern.MatchString(v) {
+		return fmt.Errorf("is not a Go version; expected a version like 1.24, 1.24.1 or 1.25rc1")
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name string
+		// wantErr is a substring of the expected error; empty means valid
+		wantErr string
+	}{
+		{"myapi", ""},
+		{"my-api", ""},
+		{"my_api2", ""},
+		{"MyAPI", ""},
+		{"", "required"},
+		{".", "cannot be . or .."},
+		{"..", "cannot be . or .."},
+		{"my api", `try "my-api"`},
+		{"my\tapi", "cannot contain spaces"},
+		{"user/myapi", "cannot contain slashes"},
+		{`user\myapi`, "cannot contain slashes"},
+		{"my:api", "cannot contain any of"},
+		{"-api", "cannot start with '-' or '.'"},
+		{".api", "cannot start with '-' or '.'"},
+		{"con", "reserved file name on Windows"},
+		{"LPT1.txt", "reserved file name on Windows"},
+		{"api\x00", "control characters"},
+		{"_-_", "at least one letter"},
+		{"2fast", "must start with a letter"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateName(tt.name)
+		checkValidation(t, "ValidateName", tt.name, err, tt.wantErr)
+	}
+}
+
+func TestValidateModulePath(t *testing.T) {
+	tests := []struct {
+		module  string
+		wantErr string
+	}{
+		{"github.com/user/myapi", ""},
+		{"github.com/User/MyAPI", ""},
+		{"example.com/myapi/v2", ""},
+		{"myapi", ""},
+		{"gopkg.in/yaml.v3", ""},
+		{"", "required"},
+		{"GitHub.com/user/myapi", `the host "GitHub.com" may only contain lower-case letters`},
+		{"-example.com/myapi", "cannot start with a hyphen"},
+		{"/github.com/user", "cannot start or end with a slash"},
+		{"github.com/user/", "cannot start or end with a slash"},
+		{"github.com//myapi", "empty path elements"},
+		{"github.com/user/my api", "cannot contain spaces"},
+		{"github.com/user/my@api", `invalid character '@'`},
+		{"github.com/../myapi", "cannot be . or .."},
+		{"github.com/user/myapi.", "cannot end with a dot"},
+		{"github.com/.user/myapi", "cannot start with a dot"},
+		{"github.com/user/aux", "reserved file name on Windows"},
+		{"github.com/user/myapi/v1", "major version suffix /v1"},
+		{"github.com/user/myapi/v0", "major version suffix /v0"},
+		{"github.com/user/\xff", "not valid UTF-8"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateModulePath(tt.module)
+		checkValidation(t, "ValidateModulePath", tt.module, err, tt.wantErr)
+	}
+}
+
+func TestValidateGoVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr string
+	}{
+		{"1.24", ""},
+		{"1.24.1", ""},
+		{"1.21rc1", ""},
+		{"1.22beta2", ""},
+		{"1.0", ""},
+		{"", "required"},
+		{"banana", "is not a Go version"},
+		{"go1.24", `use "1.24"`},
+		{"1", "is not a Go version"},
+		{"1.24.1rc1", "is not a Go version"},
+		{"1.21rc0", "is not a Go version"},
+		{"1.021", "is not a Go version"},
+		{"0.9", "is not a Go version"},
+		{"1.24 ", "is not a Go version"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateGoVersion(tt.version)
+		checkValidation(t, "ValidateGoVersion", tt.version, err, tt.wantErr)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := ProjectConfig{
+		Name:      "myapi",
+		Module:    "github.com/user/myapi",
+		Type:      ProjectTypeAPI,
+		GoVersion: "1.24",
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *ProjectConfig)
+		// fields lists the fields with problems, in order
+		fields []string
+	}{
+		{
+			name:   "valid",
+			change: func(c *ProjectConfig) {},
+		},
+		{
+			name:   "unknown type",
+			change: func(c *ProjectConfig) { c.Type = "webapp" },
+			fields: []string{"type"},
+		},
+		{
+			name: "unknown type with a template directory",
+			change: func(c *ProjectConfig) {
+				c.Type = "webapp"
+				c.TemplateDir = "templates"
+			},
+		},
+		{
+			name: "every problem at once",
+			change: func(c *ProjectConfig) {
+				c.Name = "my api"
+				c.Module = "GitHub.com/user/myapi"
+				c.GoVersion = "banana"
+				c.Type = "webapp"
+				c.GoCheck = "loud"
+				c.OnConflict = "ask"
+			},
+			fields: []string{"name", "module", "go version", "type", "go-check", "on-conflict"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			tt.change(&c)
+			err := c.Validate()
+
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Validate() error = %v, want a *ValidationError", err)
+			}
+			var fields []string
+			for _, p := range invalid.Problems {
+				fields = append(fields, p.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("Validate() problems = %v, want %v", fields, tt.fields)
+			}
+			for _, p := range invalid.Problems {
+				if !strings.Contains(err.Error(), p.Error()) {
+					t.Errorf("Validate() error %q does not list %q", err, p.Error())
+				}
+			}
+		})
+	}
+}
+
+// checkValidation compares the error of a validation function for input
+// with wantErr, a substring of the expected message or empty for none
+func checkValidation(t *testing.T, fn, input string, err error, wantErr string) {
+	t.Helper()
+	switch {
+	case wantErr == "" && err != nil:
+		t.Errorf("%s(%q) error = %v, want none", fn, input, err)
+	case wantErr != "" && err == nil:
+		t.Errorf("%s(%q) succeeded, want an error containing %q", fn, input, wantErr)
+	case wantErr != "" && !strings.Contains(err.Error(), wantErr):
+		t.Errorf("%s(%q) error = %v, want it to contain %q", fn, input, err, wantErr)
+	}
+}
@@ -0,0 +1,4 @@
+package generator
+
+// Version is the go-projo release, recorded in every generated project
+var Version = "1.0.0"